@@ -0,0 +1,322 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	w3s "github.com/web3-storage/go-w3s-client"
+	w3fs "github.com/web3-storage/go-w3s-client/fs"
+
+	nmtnamespace "github.com/celestiaorg/nmt/namespace"
+)
+
+// ErrArchivalNotConfigured is returned by CoreAccessor archival methods when
+// no ArchivalBackend was supplied via WithArchivalBackend.
+var ErrArchivalNotConfigured = errors.New("state: no archival backend configured")
+
+// ErrNoArchivedData is returned by ArchivedCID and GetArchivedBlob when no
+// archival index has been persisted for the given height.
+var ErrNoArchivedData = errors.New("state: no archived data for height")
+
+// archivalStorePrefix namespaces all archival index keys within the
+// datastore handed to an ArchivalBackend, so it can safely share a root
+// datastore with other node subsystems.
+var archivalStorePrefix = datastore.NewKey("archival")
+
+// ArchivalEntry is a single successful PayForData submission to be mirrored
+// to an ArchivalBackend.
+type ArchivalEntry struct {
+	Height      int64
+	TxHash      string
+	NamespaceID nmtnamespace.ID
+	Blob        []byte
+	Timestamp   time.Time
+}
+
+// ArchivalBackend durably mirrors PayForData blob submissions to an
+// off-chain store, complementing the on-chain PFD tx with durable
+// redundancy of the data a light-node operator paid to publish.
+type ArchivalBackend interface {
+	// Archive persists the blob described by entry. Implementations may
+	// batch entries before committing them to the backing store.
+	Archive(ctx context.Context, entry ArchivalEntry) error
+	// ArchivedCID returns the root CID under which blobs submitted at the
+	// given block height were archived.
+	ArchivedCID(ctx context.Context, height int64) (string, error)
+	// GetArchivedBlob retrieves the blob submitted for the given namespace
+	// at the given block height.
+	GetArchivedBlob(ctx context.Context, height int64, nID nmtnamespace.ID) ([]byte, error)
+}
+
+// archivedBlob is a single blob queued for inclusion in the next UnixFS
+// directory batch.
+type archivedBlob struct {
+	name string // "<namespaceID-hex>-<txHash>"
+	data []byte
+}
+
+// heightIndex is the datastore record persisted per archived block height,
+// allowing GetArchivedBlob and ArchivedCID to resolve a height back to the
+// web3.storage root CID holding a given blob. A height can accumulate more
+// than one batch (e.g. a late PFD submission arrives after the first
+// batch's timer already flushed), so Files maps each blob's name directly
+// to the root CID of the batch it was actually uploaded in, rather than
+// assuming every blob archived at a height lives under a single root.
+type heightIndex struct {
+	// RootCID is the root CID of the most recently uploaded batch for this
+	// height. Files resolves individual blobs to the (possibly different)
+	// root CID that actually holds them.
+	RootCID string            `json:"rootCid"`
+	Files   map[string]string `json:"files"` // namespaceID-hex-txHash -> root CID holding that file
+}
+
+// Web3StorageBackend is an ArchivalBackend that batches blobs into a UnixFS
+// directory keyed by block height and uploads it to web3.storage, backed by
+// Filecoin/IPFS for durability.
+type Web3StorageBackend struct {
+	cli w3s.Client
+	ds  datastore.Datastore
+
+	batchWindow time.Duration
+
+	mu      sync.Mutex
+	pending map[int64][]archivedBlob
+	timers  map[int64]*time.Timer
+}
+
+// Web3StorageConfig configures a Web3StorageBackend.
+type Web3StorageConfig struct {
+	// Token is the web3.storage API token used to authenticate uploads.
+	Token string
+	// Endpoint overrides the default web3.storage API endpoint, mainly
+	// useful for testing against a local mock.
+	Endpoint string
+	// BatchWindow is how long blobs submitted at the same block height are
+	// buffered before being uploaded as a single UnixFS directory.
+	BatchWindow time.Duration
+}
+
+// NewWeb3StorageBackend constructs a Web3StorageBackend that uploads batched
+// blobs to web3.storage and persists its CID index in ds.
+func NewWeb3StorageBackend(cfg Web3StorageConfig, ds datastore.Datastore) (*Web3StorageBackend, error) {
+	if cfg.Token == "" {
+		return nil, errors.New("state: web3.storage API token must be set")
+	}
+	opts := []w3s.Option{w3s.WithToken(cfg.Token)}
+	if cfg.Endpoint != "" {
+		opts = append(opts, w3s.WithEndpoint(cfg.Endpoint))
+	}
+	cli, err := w3s.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("state: constructing web3.storage client: %w", err)
+	}
+	if cfg.BatchWindow <= 0 {
+		cfg.BatchWindow = 10 * time.Second
+	}
+
+	return &Web3StorageBackend{
+		cli:         cli,
+		ds:          namespace.Wrap(ds, archivalStorePrefix),
+		batchWindow: cfg.BatchWindow,
+		pending:     make(map[int64][]archivedBlob),
+		timers:      make(map[int64]*time.Timer),
+	}, nil
+}
+
+// Archive buffers the entry's blob under its block height and schedules a
+// flush of the height's batch after the configured batch window, so that
+// multiple PFD submissions landing in the same block are uploaded together
+// as a single UnixFS directory.
+func (w *Web3StorageBackend) Archive(ctx context.Context, entry ArchivalEntry) error {
+	blob := archivedBlob{
+		name: fmt.Sprintf("%x-%s", entry.NamespaceID, entry.TxHash),
+		data: entry.Blob,
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending[entry.Height] = append(w.pending[entry.Height], blob)
+	if _, scheduled := w.timers[entry.Height]; scheduled {
+		return nil
+	}
+	height := entry.Height
+	w.timers[entry.Height] = time.AfterFunc(w.batchWindow, func() {
+		if err := w.flush(context.Background(), height); err != nil {
+			log.Errorf("flushing archival batch for height %d: %s", height, err)
+		}
+	})
+	return nil
+}
+
+// flush uploads every blob buffered for height as a single UnixFS directory
+// and persists the resulting root CID index.
+func (w *Web3StorageBackend) flush(ctx context.Context, height int64) error {
+	w.mu.Lock()
+	blobs := w.pending[height]
+	delete(w.pending, height)
+	delete(w.timers, height)
+	w.mu.Unlock()
+
+	if len(blobs) == 0 {
+		return nil
+	}
+
+	files := make([]fs.File, len(blobs))
+	names := make(map[string]string, len(blobs))
+	for i, blob := range blobs {
+		files[i] = newMemFile(blob.name, blob.data)
+		names[blob.name] = blob.name
+	}
+	dir := w3fs.NewDir(fmt.Sprintf("%d", height), files)
+
+	rootCID, err := w.cli.Put(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("uploading batch to web3.storage: %w", err)
+	}
+
+	return w.mergeIndex(ctx, height, rootCID.String(), names)
+}
+
+// ArchivedCID returns the root CID of the most recently uploaded batch for
+// height. A height that accumulated more than one batch (see mergeIndex)
+// has blobs spread across multiple root CIDs; GetArchivedBlob resolves
+// those individually, but ArchivedCID can only surface one, so callers
+// that need every root for a height should prefer GetArchivedBlob per blob.
+func (w *Web3StorageBackend) ArchivedCID(ctx context.Context, height int64) (string, error) {
+	idx, err := w.getIndex(ctx, height)
+	if err != nil {
+		return "", err
+	}
+	return idx.RootCID, nil
+}
+
+// GetArchivedBlob retrieves the blob submitted for nID at height by looking
+// up the root CID that batch was actually uploaded under, fetching the
+// UnixFS directory behind it, and reading the file that matches nID.
+func (w *Web3StorageBackend) GetArchivedBlob(ctx context.Context, height int64, nID nmtnamespace.ID) ([]byte, error) {
+	idx, err := w.getIndex(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := fmt.Sprintf("%x-", nID)
+	var fileName, fileRootCID string
+	for name, root := range idx.Files {
+		if len(name) >= len(prefix) && name[:len(prefix)] == prefix {
+			fileName, fileRootCID = name, root
+			break
+		}
+	}
+	if fileName == "" {
+		return nil, fmt.Errorf("state: no archived blob for namespace %x at height %d", nID, height)
+	}
+
+	rootCID, err := cid.Decode(fileRootCID)
+	if err != nil {
+		return nil, err
+	}
+	res, err := w.cli.Get(ctx, rootCID)
+	if err != nil {
+		return nil, err
+	}
+	_, fsys, err := res.Files()
+	if err != nil {
+		return nil, err
+	}
+	f, err := fsys.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return io.ReadAll(f)
+}
+
+// mergeIndex folds a newly uploaded batch's root CID and file list into
+// whatever heightIndex is already persisted for height, instead of
+// overwriting it. Without this, a second batch flushed for a height whose
+// first batch already flushed (e.g. a late Archive call for a height whose
+// timer already fired) would wipe out the first batch's root CID and file
+// list, leaving its blobs unreachable even though they're still pinned.
+func (w *Web3StorageBackend) mergeIndex(ctx context.Context, height int64, rootCID string, names map[string]string) error {
+	idx, err := w.getIndex(ctx, height)
+	if err != nil && !errors.Is(err, ErrNoArchivedData) {
+		return err
+	}
+	if idx.Files == nil {
+		idx.Files = make(map[string]string, len(names))
+	}
+	for name := range names {
+		idx.Files[name] = rootCID
+	}
+	idx.RootCID = rootCID
+	return w.putIndex(ctx, height, idx)
+}
+
+func (w *Web3StorageBackend) putIndex(ctx context.Context, height int64, idx heightIndex) error {
+	raw, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	return w.ds.Put(ctx, heightKey(height), raw)
+}
+
+func (w *Web3StorageBackend) getIndex(ctx context.Context, height int64) (heightIndex, error) {
+	raw, err := w.ds.Get(ctx, heightKey(height))
+	if err != nil {
+		if errors.Is(err, datastore.ErrNotFound) {
+			return heightIndex{}, fmt.Errorf("%w: height %d", ErrNoArchivedData, height)
+		}
+		return heightIndex{}, err
+	}
+	var idx heightIndex
+	if err := json.Unmarshal(raw, &idx); err != nil {
+		return heightIndex{}, err
+	}
+	return idx, nil
+}
+
+func heightKey(height int64) datastore.Key {
+	return datastore.NewKey(fmt.Sprintf("%d", height))
+}
+
+// memFile adapts an in-memory blob to the fs.File interface expected by
+// w3fs.NewDir, avoiding a round-trip through disk for data that already
+// lives in memory as part of a PayForData submission.
+type memFile struct {
+	*bytes.Reader
+	info memFileInfo
+}
+
+func newMemFile(name string, data []byte) *memFile {
+	return &memFile{
+		Reader: bytes.NewReader(data),
+		info:   memFileInfo{name: name, size: int64(len(data))},
+	}
+}
+
+func (m *memFile) Stat() (fs.FileInfo, error) { return m.info, nil }
+func (m *memFile) Close() error               { return nil }
+
+type memFileInfo struct {
+	name string
+	size int64
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return i.size }
+func (i memFileInfo) Mode() fs.FileMode  { return 0o444 }
+func (i memFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memFileInfo) IsDir() bool        { return false }
+func (i memFileInfo) Sys() any           { return nil }
@@ -1,555 +1,904 @@
 package state
 
 import (
-    "context"
-    "errors"
-    "fmt"
-    "time"
-	"io"
-	"io/fs"
-	"os"
-
-    "github.com/cosmos/cosmos-sdk/api/tendermint/abci"
-    "github.com/cosmos/cosmos-sdk/store/rootmulti"
-    sdktypes "github.com/cosmos/cosmos-sdk/types"
-    sdktx "github.com/cosmos/cosmos-sdk/types/tx"
-    banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
-    stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
-    logging "github.com/ipfs/go-log/v2"
-    rpcclient "github.com/tendermint/tendermint/rpc/client"
-    "github.com/tendermint/tendermint/rpc/client/http"
-    "google.golang.org/grpc"
-    "google.golang.org/grpc/credentials/insecure"
-
-    "github.com/celestiaorg/celestia-app/app"
-    "github.com/celestiaorg/celestia-app/x/payment"
-    apptypes "github.com/celestiaorg/celestia-app/x/payment/types"
-    "github.com/celestiaorg/nmt/namespace"
-
-    "github.com/celestiaorg/celestia-node/header"
-
-	"github.com/ipfs/go-cid"
-	"github.com/web3-storage/go-w3s-client"
-	w3fs "github.com/web3-storage/go-w3s-client/fs"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/api/tendermint/abci"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	logging "github.com/ipfs/go-log/v2"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	"google.golang.org/grpc"
+
+	"github.com/celestiaorg/celestia-app/app"
+	"github.com/celestiaorg/celestia-app/x/payment"
+	apptypes "github.com/celestiaorg/celestia-app/x/payment/types"
+	"github.com/celestiaorg/nmt/namespace"
+
+	"github.com/celestiaorg/celestia-node/header"
 )
 
-// Usage:
-// TOKEN="API_TOKEN" go run ./main.go
-func main() {
-	c, err := w3s.NewClient(
-		w3s.WithEndpoint(os.Getenv("ENDPOINT")),
-		w3s.WithToken(os.Getenv("TOKEN")),
-	)
-	if err != nil {
-		panic(err)
-	}
-
-	// cid := putSingleFile(c)
-	// getStatusForCid(c, cid)
-	// getStatusForKnownCid(c)
-	getFiles(c)
-	// listUploads(c)
-}
-
 var (
-    log              = logging.Logger("state")
-    ErrInvalidAmount = errors.New("state: amount must be greater than zero")
+	log              = logging.Logger("state")
+	ErrInvalidAmount = errors.New("state: amount must be greater than zero")
+	// ErrNoSigner is returned by tx-submitting and signer-address-dependent
+	// methods when the CoreAccessor was constructed without a Signer,
+	// i.e. it is running in lite/read-only mode.
+	ErrNoSigner = errors.New("state: no signer configured; core accessor is in lite/read-only mode")
+	// ErrPayForDataRequiresKeyringSigner is returned by SubmitPayForData
+	// when the configured Signer is not a *KeyringSigner. The upstream
+	// payment.SubmitPayForData helper builds, signs, and broadcasts a PFD
+	// tx in one call against a concrete *apptypes.KeyringSigner, bypassing
+	// CoreAccessor's Signer.Sign hook entirely, so RemoteSigner and
+	// OfflineSigner cannot currently back PayForData submissions.
+	ErrPayForDataRequiresKeyringSigner = errors.New("state: SubmitPayForData requires a KeyringSigner")
 )
 
 // CoreAccessor implements service over a gRPC connection
 // with a celestia-core node.
 type CoreAccessor struct {
-    ctx    context.Context
-    cancel context.CancelFunc
-
-    signer *apptypes.KeyringSigner
-    getter header.Head
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// signer is nil in lite/read-only mode, where CoreAccessor serves
+	// balance and chain queries but rejects tx-submitting methods.
+	signer Signer
+	getter header.Head
+
+	queryCli   banktypes.QueryClient
+	stakingCli stakingtypes.QueryClient
+	govCli     govtypes.QueryClient
+	rpcCli     rpcclient.ABCIClient
+	healthCli  gasRPCClient
+
+	// connMu guards coreConn and every client/estimator derived from it,
+	// plus endpoints/activeIdx/lastErr, all of which dial and failover
+	// swap out from under in-flight calls.
+	connMu    sync.RWMutex
+	coreConn  *grpc.ClientConn
+	endpoints []Endpoint
+	activeIdx int
+	lastErr   error
+
+	superviseCancel context.CancelFunc
+	superviseDone   chan struct{}
+
+	gasPriceCancel context.CancelFunc
+	gasPriceDone   chan struct{}
+
+	archival ArchivalBackend
+
+	txServiceCli     sdktx.ServiceClient
+	gasEstimator     *GasEstimator
+	gasEstimatorOpts []GasEstimatorOption
+
+	lastPayForData  int64
+	payForDataCount int64
+}
 
-    queryCli   banktypes.QueryClient
-    stakingCli stakingtypes.QueryClient
-    rpcCli     rpcclient.ABCIClient
+// Option configures optional CoreAccessor behavior at construction time.
+type Option func(*CoreAccessor)
 
-    coreConn *grpc.ClientConn
-    coreIP   string
-    rpcPort  string
-    grpcPort string
+// WithArchivalBackend configures the CoreAccessor to mirror every successful
+// SubmitPayForData payload to the given ArchivalBackend, giving light-node
+// operators durable off-chain redundancy of the data they paid to publish.
+func WithArchivalBackend(backend ArchivalBackend) Option {
+	return func(ca *CoreAccessor) {
+		ca.archival = backend
+	}
+}
 
-    lastPayForData  int64
-    payForDataCount int64
+// WithGasEstimatorOptions configures the GasEstimator that CoreAccessor
+// builds against the core endpoint once Start dials it.
+func WithGasEstimatorOptions(opts ...GasEstimatorOption) Option {
+	return func(ca *CoreAccessor) {
+		ca.gasEstimatorOpts = opts
+	}
 }
 
-// NewCoreAccessor dials the given celestia-core endpoint and
-// constructs and returns a new CoreAccessor (state service) with the active
-// connection.
+// NewCoreAccessor constructs a new CoreAccessor (state service) against the
+// given pool of celestia-core endpoints. Start dials the first reachable
+// endpoint in the pool; a supervising goroutine thereafter fails over to
+// the next one on connection loss. signer may be nil, in which case the
+// CoreAccessor starts in lite/read-only mode and rejects tx-submitting
+// methods with ErrNoSigner.
 func NewCoreAccessor(
-    signer *apptypes.KeyringSigner,
-    getter header.Head,
-    coreIP,
-    rpcPort string,
-    grpcPort string,
+	signer Signer,
+	getter header.Head,
+	endpoints []Endpoint,
+	opts ...Option,
 ) *CoreAccessor {
-    return &CoreAccessor{
-        signer:   signer,
-        getter:   getter,
-        coreIP:   coreIP,
-        rpcPort:  rpcPort,
-        grpcPort: grpcPort,
-    }
+	ca := &CoreAccessor{
+		signer:    signer,
+		getter:    getter,
+		endpoints: endpoints,
+	}
+	for _, opt := range opts {
+		opt(ca)
+	}
+	return ca
 }
 
 func (ca *CoreAccessor) Start(ctx context.Context) error {
-    if ca.coreConn != nil {
-        return fmt.Errorf("core-access: already connected to core endpoint")
-    }
-    ca.ctx, ca.cancel = context.WithCancel(context.Background())
-
-    // dial given celestia-core endpoint
-    endpoint := fmt.Sprintf("%s:%s", ca.coreIP, ca.grpcPort)
-    client, err := grpc.DialContext(ctx, endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
-    if err != nil {
-        return err
-    }
-    ca.coreConn = client
-    // create the query client
-    queryCli := banktypes.NewQueryClient(ca.coreConn)
-    ca.queryCli = queryCli
-    // create the staking query client
-    stakingCli := stakingtypes.NewQueryClient(ca.coreConn)
-    ca.stakingCli = stakingCli
-    // create ABCI query client
-    cli, err := http.New(fmt.Sprintf("http://%s:%s", ca.coreIP, ca.rpcPort), "/websocket")
-    if err != nil {
-        return err
-    }
-    ca.rpcCli = cli
-    return nil
+	if ca.coreConn != nil {
+		return fmt.Errorf("core-access: already connected to core endpoint")
+	}
+	if len(ca.endpoints) == 0 {
+		return fmt.Errorf("core-access: no endpoints configured")
+	}
+	ca.ctx, ca.cancel = context.WithCancel(context.Background())
+
+	if err := ca.dial(ctx, 0); err != nil {
+		return err
+	}
+
+	superviseCtx, cancel := context.WithCancel(ca.ctx)
+	ca.superviseCancel = cancel
+	ca.superviseDone = make(chan struct{})
+	go ca.superviseConnection(superviseCtx)
+
+	gasPriceCtx, gasPriceCancel := context.WithCancel(ca.ctx)
+	ca.gasPriceCancel = gasPriceCancel
+	ca.gasPriceDone = make(chan struct{})
+	go ca.superviseGasPrice(gasPriceCtx)
+	return nil
 }
 
 func (ca *CoreAccessor) Stop(context.Context) error {
-    if ca.cancel == nil {
-        log.Warn("core accessor already stopped")
-        return nil
-    }
-    if ca.coreConn == nil {
-        log.Warn("no connection found to close")
-        return nil
-    }
-    defer ca.cancelCtx()
-
-    // close out core connection
-    err := ca.coreConn.Close()
-    if err != nil {
-        return err
-    }
-
-    ca.coreConn = nil
-    ca.queryCli = nil
-    return nil
+	if ca.cancel == nil {
+		log.Warn("core accessor already stopped")
+		return nil
+	}
+	if ca.coreConn == nil {
+		log.Warn("no connection found to close")
+		return nil
+	}
+	defer ca.cancelCtx()
+
+	if ca.superviseCancel != nil {
+		ca.superviseCancel()
+		<-ca.superviseDone
+	}
+	if ca.gasPriceCancel != nil {
+		ca.gasPriceCancel()
+		<-ca.gasPriceDone
+	}
+
+	// close out core connection
+	err := ca.coreConn.Close()
+	if err != nil {
+		return err
+	}
+
+	ca.coreConn = nil
+	ca.queryCli = nil
+	return nil
 }
 
 func (ca *CoreAccessor) cancelCtx() {
-    ca.cancel()
-    ca.cancel = nil
+	ca.cancel()
+	ca.cancel = nil
 }
 
 func (ca *CoreAccessor) constructSignedTx(
-    ctx context.Context,
-    msg sdktypes.Msg,
-    opts ...apptypes.TxBuilderOption,
+	ctx context.Context,
+	msg sdktypes.Msg,
+	opts ...apptypes.TxBuilderOption,
 ) ([]byte, error) {
-    // should be called first in order to make a valid tx
-    err := ca.signer.QueryAccountNumber(ctx, ca.coreConn)
-    if err != nil {
-        return nil, err
-    }
+	if ca.signer == nil {
+		return nil, ErrNoSigner
+	}
+	ca.connMu.RLock()
+	conn := ca.coreConn
+	ca.connMu.RUnlock()
+	return ca.signer.Sign(ctx, conn, msg, opts...)
+}
+
+// signerAddress returns the configured signer's account address, or
+// ErrNoSigner if the CoreAccessor is in lite/read-only mode.
+func (ca *CoreAccessor) signerAddress() (Address, error) {
+	if ca.signer == nil {
+		return nil, ErrNoSigner
+	}
+	return ca.signer.Address()
+}
 
-    tx, err := ca.signer.BuildSignedTx(ca.signer.NewTxBuilder(opts...), msg)
-    if err != nil {
-        return nil, err
-    }
-    return ca.signer.EncodeTx(tx)
+// BroadcastOnly submits an already-signed tx without requiring a Signer to
+// be configured, e.g. a tx produced by an OfflineSigner's unsigned/signed
+// round trip on a separate, air-gapped machine.
+func (ca *CoreAccessor) BroadcastOnly(ctx context.Context, signedTx Tx) (*TxResponse, error) {
+	return ca.SubmitTx(ctx, signedTx)
+}
+
+// simulationGasLimit is a generous gas limit used only to build the tx
+// that gets simulated by EstimateGas; the simulation result's GasUsed, not
+// this ceiling, determines the returned gas limit.
+const simulationGasLimit = 10_000_000
+
+// EstimateGas simulates msg against the connected core endpoint to size a
+// recommended gas limit (simulated GasUsed scaled by a gas adjustment
+// multiplier) and pairs it with the gas estimator's current suggested gas
+// price, so callers no longer need to guess a raw gasLim.
+func (ca *CoreAccessor) EstimateGas(ctx context.Context, msg sdktypes.Msg) (gasLim uint64, gasPrice sdktypes.Dec, err error) {
+	ca.connMu.RLock()
+	gasEstimator := ca.gasEstimator
+	txServiceCli := ca.txServiceCli
+	ca.connMu.RUnlock()
+	if gasEstimator == nil {
+		return 0, sdktypes.Dec{}, ErrGasEstimatorNotConfigured
+	}
+
+	txBytes, err := ca.constructSignedTx(ctx, msg, apptypes.SetGasLimit(simulationGasLimit))
+	if err != nil {
+		return 0, sdktypes.Dec{}, err
+	}
+	simRes, err := txServiceCli.Simulate(ctx, &sdktx.SimulateRequest{TxBytes: txBytes})
+	if err != nil {
+		return 0, sdktypes.Dec{}, err
+	}
+
+	gasPrice, err = gasEstimator.SuggestGasPrice(ctx)
+	if err != nil {
+		return 0, sdktypes.Dec{}, err
+	}
+	gasLim = uint64(float64(simRes.GasInfo.GasUsed) * gasEstimator.gasAdjustment)
+	return gasLim, gasPrice, nil
+}
+
+// SuggestGasPrice returns the gas estimator's current recommended minimum
+// gas price, derived from a rolling window of recent block gas utilization.
+func (ca *CoreAccessor) SuggestGasPrice(ctx context.Context) (sdktypes.Dec, error) {
+	ca.connMu.RLock()
+	gasEstimator := ca.gasEstimator
+	ca.connMu.RUnlock()
+	if gasEstimator == nil {
+		return sdktypes.Dec{}, ErrGasEstimatorNotConfigured
+	}
+	return gasEstimator.SuggestGasPrice(ctx)
+}
+
+// gasPricePollInterval bounds how often superviseGasPrice checks ca.getter
+// for a new head between refreshes of the gas estimator.
+const gasPricePollInterval = 2 * time.Second
+
+// superviseGasPrice polls ca.getter for new heads and refreshes the gas
+// estimator once per new head, until ctx is done. SuggestGasPrice and
+// EstimateGas still call Refresh on every call, but that call usually finds
+// the latest height already sampled and returns without fetching block
+// results.
+func (ca *CoreAccessor) superviseGasPrice(ctx context.Context) {
+	defer close(ca.gasPriceDone)
+
+	var lastHeight int64
+	ticker := time.NewTicker(gasPricePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			head, err := ca.getter.Head(ctx)
+			if err != nil {
+				log.Warnw("core-access: fetching head for gas price refresh", "err", err)
+				continue
+			}
+			if head.Height <= lastHeight {
+				continue
+			}
+			lastHeight = head.Height
+
+			ca.connMu.RLock()
+			gasEstimator := ca.gasEstimator
+			ca.connMu.RUnlock()
+			if gasEstimator == nil {
+				continue
+			}
+			if err := gasEstimator.Refresh(ctx); err != nil {
+				log.Warnw("core-access: refreshing gas price estimate", "err", err)
+			}
+		}
+	}
 }
 
 func (ca *CoreAccessor) SubmitPayForData(
-    ctx context.Context,
-    nID namespace.ID,
-    data []byte,
-    gasLim uint64,
+	ctx context.Context,
+	nID namespace.ID,
+	data []byte,
+	gasLim uint64,
 ) (*TxResponse, error) {
-    response, err := payment.SubmitPayForData(ctx, ca.signer, ca.coreConn, nID, data, gasLim)
-    // metrics should only be counted on a successful PFD tx
-    if response.Code == 0 && err == nil {
-        ca.lastPayForData = time.Now().UnixMilli()
-        ca.payForDataCount++
-    }
-    return response, err
+	if ca.signer == nil {
+		return nil, ErrNoSigner
+	}
+	keyringSigner, ok := ca.signer.(*KeyringSigner)
+	if !ok {
+		return nil, ErrPayForDataRequiresKeyringSigner
+	}
+	var response *TxResponse
+	err := ca.withRetry(ctx, func() error {
+		ca.connMu.RLock()
+		conn := ca.coreConn
+		ca.connMu.RUnlock()
+		var submitErr error
+		response, submitErr = payment.SubmitPayForData(ctx, keyringSigner.signer, conn, nID, data, gasLim)
+		return submitErr
+	})
+	// metrics should only be counted on a successful PFD tx
+	if err == nil && response.Code == 0 {
+		ca.lastPayForData = time.Now().UnixMilli()
+		ca.payForDataCount++
+		ca.archiveBlob(nID, data, response)
+	}
+	return response, err
+}
+
+// SubmitPayForDataWithFee behaves like SubmitPayForData, but when opts.Auto
+// is set, sizes the gas limit by applying the GasEstimator's gasAdjustment
+// multiplier to the caller-supplied base estimate in opts.GasLim, the same
+// headroom EstimateGas applies to a simulated tx's GasUsed. Unlike the other
+// *WithFee wrappers, the upstream payment.SubmitPayForData helper builds and
+// broadcasts the PFD tx itself and takes no fee amount, so opts.GasPrice and
+// the estimator's suggested gas price cannot actually be applied to this
+// transaction; the connected core node's own minimum gas price still governs.
+func (ca *CoreAccessor) SubmitPayForDataWithFee(
+	ctx context.Context,
+	nID namespace.ID,
+	data []byte,
+	opts FeeOpts,
+) (*TxResponse, error) {
+	gasLim := opts.GasLim
+	if opts.Auto {
+		ca.connMu.RLock()
+		gasEstimator := ca.gasEstimator
+		ca.connMu.RUnlock()
+		if gasEstimator == nil {
+			return nil, ErrGasEstimatorNotConfigured
+		}
+		gasLim = uint64(float64(gasLim) * gasEstimator.gasAdjustment)
+	}
+	return ca.SubmitPayForData(ctx, nID, data, gasLim)
+}
+
+// archiveBlob mirrors a successful PayForData submission to the configured
+// ArchivalBackend, if any. Archival is best-effort: operators that did not
+// opt into an ArchivalBackend pay no cost, and a transient archival error
+// must not be surfaced as a PFD failure since the tx itself already landed.
+func (ca *CoreAccessor) archiveBlob(nID namespace.ID, data []byte, response *TxResponse) {
+	if ca.archival == nil {
+		return
+	}
+	err := ca.archival.Archive(ca.ctx, ArchivalEntry{
+		Height:      response.Height,
+		TxHash:      response.TxHash,
+		NamespaceID: nID,
+		Blob:        data,
+		Timestamp:   time.Now(),
+	})
+	if err != nil {
+		log.Errorw("archiving PayForData blob", "height", response.Height, "txHash", response.TxHash, "err", err)
+	}
+}
+
+// ArchivedCID returns the root CID under which the blobs submitted at the
+// given block height were archived by the configured ArchivalBackend.
+func (ca *CoreAccessor) ArchivedCID(ctx context.Context, height int64) (string, error) {
+	if ca.archival == nil {
+		return "", ErrArchivalNotConfigured
+	}
+	return ca.archival.ArchivedCID(ctx, height)
+}
+
+// GetArchivedBlob retrieves the blob submitted for the given namespace at the
+// given block height from the configured ArchivalBackend.
+func (ca *CoreAccessor) GetArchivedBlob(ctx context.Context, height int64, nID namespace.ID) ([]byte, error) {
+	if ca.archival == nil {
+		return nil, ErrArchivalNotConfigured
+	}
+	return ca.archival.GetArchivedBlob(ctx, height, nID)
 }
 
 func (ca *CoreAccessor) AccountAddress(ctx context.Context) (Address, error) {
-    addr, err := ca.signer.GetSignerInfo().GetAddress()
-    if err != nil {
-        return nil, err
-    }
-    return addr, nil
+	addr, err := ca.signerAddress()
+	if err != nil {
+		return nil, err
+	}
+	return addr, nil
 }
 
 func (ca *CoreAccessor) Balance(ctx context.Context) (*Balance, error) {
-    addr, err := ca.signer.GetSignerInfo().GetAddress()
-    if err != nil {
-        return nil, err
-    }
-    return ca.BalanceForAddress(ctx, addr)
+	addr, err := ca.signerAddress()
+	if err != nil {
+		return nil, err
+	}
+	return ca.BalanceForAddress(ctx, addr)
 }
 
 func (ca *CoreAccessor) BalanceForAddress(ctx context.Context, addr Address) (*Balance, error) {
-    head, err := ca.getter.Head(ctx)
-    if err != nil {
-        return nil, err
-    }
-    // construct an ABCI query for the height at head-1 because
-    // the AppHash contained in the head is actually the state root
-    // after applying the transactions contained in the previous block.
-    // TODO @renaynay: once https://github.com/cosmos/cosmos-sdk/pull/12674 is merged, use this method
-    // instead
-    prefixedAccountKey := append(banktypes.CreateAccountBalancesPrefix(addr.Bytes()), []byte(app.BondDenom)...)
-    abciReq := abci.RequestQuery{
-        // TODO @renayay: once https://github.com/cosmos/cosmos-sdk/pull/12674 is merged, use const instead
-        Path:   fmt.Sprintf("store/%s/key", banktypes.StoreKey),
-        Height: head.Height - 1,
-        Data:   prefixedAccountKey,
-        Prove:  true,
-    }
-    opts := rpcclient.ABCIQueryOptions{
-        Height: abciReq.Height,
-        Prove:  abciReq.Prove,
-    }
-    result, err := ca.rpcCli.ABCIQueryWithOptions(ctx, abciReq.Path, abciReq.Data, opts)
-    if err != nil {
-        return nil, err
-    }
-    if !result.Response.IsOK() {
-        return nil, sdkErrorToGRPCError(result.Response)
-    }
-    // unmarshal balance information
-    value := result.Response.Value
-    // if the value returned is empty, the account balance does not yet exist
-    if len(value) == 0 {
-        log.Errorf("balance for account %s does not exist at block height %d", addr.String(), head.Height-1)
-        return &Balance{
-            Denom:  app.BondDenom,
-            Amount: sdktypes.NewInt(0),
-        }, nil
-    }
-    coin, ok := sdktypes.NewIntFromString(string(value))
-    if !ok {
-        return nil, fmt.Errorf("cannot convert %s into sdktypes.Int", string(value))
-    }
-    // verify balance
-    path := fmt.Sprintf("/%s/%s", banktypes.StoreKey, string(prefixedAccountKey))
-    prt := rootmulti.DefaultProofRuntime()
-    err = prt.VerifyValue(result.Response.GetProofOps(), head.AppHash, path, value)
-    if err != nil {
-        return nil, err
-    }
-
-    return &Balance{
-        Denom:  app.BondDenom,
-        Amount: coin,
-    }, nil
+	head, err := ca.getter.Head(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// construct an ABCI query for the height at head-1 because
+	// the AppHash contained in the head is actually the state root
+	// after applying the transactions contained in the previous block.
+	// TODO @renaynay: once https://github.com/cosmos/cosmos-sdk/pull/12674 is merged, use this method
+	// instead
+	prefixedAccountKey := append(banktypes.CreateAccountBalancesPrefix(addr.Bytes()), []byte(app.BondDenom)...)
+	abciReq := abci.RequestQuery{
+		// TODO @renayay: once https://github.com/cosmos/cosmos-sdk/pull/12674 is merged, use const instead
+		Path:   fmt.Sprintf("store/%s/key", banktypes.StoreKey),
+		Height: head.Height - 1,
+		Data:   prefixedAccountKey,
+		Prove:  true,
+	}
+	opts := rpcclient.ABCIQueryOptions{
+		Height: abciReq.Height,
+		Prove:  abciReq.Prove,
+	}
+	var result *coretypes.ResultABCIQuery
+	err = ca.withRetry(ctx, func() error {
+		ca.connMu.RLock()
+		rpcCli := ca.rpcCli
+		ca.connMu.RUnlock()
+		var queryErr error
+		result, queryErr = rpcCli.ABCIQueryWithOptions(ctx, abciReq.Path, abciReq.Data, opts)
+		return queryErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !result.Response.IsOK() {
+		return nil, sdkErrorToGRPCError(result.Response)
+	}
+	// unmarshal balance information
+	value := result.Response.Value
+	// if the value returned is empty, the account balance does not yet exist
+	if len(value) == 0 {
+		log.Errorf("balance for account %s does not exist at block height %d", addr.String(), head.Height-1)
+		return &Balance{
+			Denom:  app.BondDenom,
+			Amount: sdktypes.NewInt(0),
+		}, nil
+	}
+	coin, ok := sdktypes.NewIntFromString(string(value))
+	if !ok {
+		return nil, fmt.Errorf("cannot convert %s into sdktypes.Int", string(value))
+	}
+	// verify balance
+	path := fmt.Sprintf("/%s/%s", banktypes.StoreKey, string(prefixedAccountKey))
+	prt := rootmulti.DefaultProofRuntime()
+	err = prt.VerifyValue(result.Response.GetProofOps(), head.AppHash, path, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Balance{
+		Denom:  app.BondDenom,
+		Amount: coin,
+	}, nil
 }
 
 func (ca *CoreAccessor) SubmitTx(ctx context.Context, tx Tx) (*TxResponse, error) {
-    txResp, err := apptypes.BroadcastTx(ctx, ca.coreConn, sdktx.BroadcastMode_BROADCAST_MODE_BLOCK, tx)
-    if err != nil {
-        return nil, err
-    }
-    return txResp.TxResponse, nil
-}
-
-// func putSingleFile(c w3s.Client) cid.Cid {
-// 	file, err := os.Open("images/exampleq.jpg")
-// 	if err != nil {
-// 		panic(err)
-// 	}
-// 	return putFile(c, file)
-// }
-
-func putMultipleFiles(c w3s.Client) cid.Cid {
-// 	f0, err := os.Open("images/eample.jpg")
-// 	if err != nil {
-// 		panic(err)
-// 	}
-// 	f1, err := os.Open("images/example.jpg")
-// 	if err != nil {
-// 		panic(err)
-// 	}
-	// dir := w3fs.NewDir("comic", []fs.File{f0, f1})
-	// return putFile(c, dir)
-}
-
-// func putMultipleFilesAndDirectories(c w3s.Client) cid.Cid {
-// 	f0, err := os.Open("images/examplezz.jpg")
-// 	if err != nil {
-// 		panic(err)
-// 	}
-// 	f1, err := os.Open("images/examples.jpg")
-// 	if err != nil {
-// 		panic(err)
-// 	}
-// 	d0 := w3fs.NewDir("one", []fs.File{f0})
-// 	d1 := w3fs.NewDir("two", []fs.File{f1})
-// 	rootdir := w3fs.NewDir("comic", []fs.File{d0, d1})
-// 	return putFile(c, rootdir)
-// }
-
-// func putDirectory(c w3s.Client) cid.Cid {
-// 	dir, err := os.Open("images")
-// 	if err != nil {
-// 		panic(err)
-// 	}
-// 	return putFile(c, dir)
-// }
-
-// func putFile(c w3s.Client, f fs.File, opts ...w3s.PutOption) cid.Cid {
-// 	cid, err := c.Put(context.Background(), f, opts...)
-// 	if err != nil {
-// 		panic(err)
-// 	}
-// 	fmt.Printf("https://%v.ipfs.dweb.link\n", cid)
-// 	return cid
-// }
-
-
-// write a hook that takes data and puts on Filecoin in function below
+	return ca.SubmitTxWithBroadcastMode(ctx, tx, sdktx.BroadcastMode_BROADCAST_MODE_BLOCK)
+}
+
 func (ca *CoreAccessor) SubmitTxWithBroadcastMode(
-    ctx context.Context,
-    tx Tx,
-    mode sdktx.BroadcastMode,
+	ctx context.Context,
+	tx Tx,
+	mode sdktx.BroadcastMode,
 ) (*TxResponse, error) {
-    txResp, err := apptypes.BroadcastTx(ctx, ca.coreConn, mode, tx)
-    if err != nil {
-        return nil, err
-    }
-    // first attempt a single (tx) file upload
-    // cid := putSingleFile(ca.coreConn)
-    
-    // then attempt to upload multiple tx files
-	// tx = w3fs.putFiles(c w3s.Client) cid.Cid {
-    return txResp.TxResponse, nil
-}
-
-func putFile(c w3s.Client, f fs.File, opts ...w3s.PutOption) cid.Cid {
-	// cid, err := c.Put(context.Background(), f, opts...)
-    cid, err := c.Put(context.Background(), TxResponse, opts...)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Printf("https://%v.ipfs.dweb.link\n", cid)
-	return cid
-
-// write a hook that takes data and puts on Filecoin in function below
-
-// To use Web3.storage the user must have an API token. This token can be generated once an account is created: https://web3.storage/docs/intro/#get-an-api-token
-// Ensure the proper submit PayForData is POST, with the body including a field for the file(s) uploaded to Filecoin (using Web3.storage)
-// func (ca *CoreAccessor) SubmitData(ctx context.Context, data []byte) (*TxResponse, error) {
-    // c, _ := w3s.NewClient(w3s.WithToken("<AUTH_TOKEN>"))
-    // f, _ := os.Open("images/examples.jpg")       // create image file in aforementioned directory
-    // // OR add a whole directory:
-    // //
-    // //   f, _ := os.Open("images")
-    // //
-    // // OR create your own directory:
-    // //
-    // //   img0, _ := os.Open("aliens.jpg")
-    // //   img1, _ := os.Open("donotresist.jpg")
-    // //   f := w3fs.NewDir("images", []fs.File{img0, img1})
-
-    // // Write a file/directory
-    // cid, _ := c.Put(context.Background(), f)
-    // fmt.Printf("https://%v.ipfs.dweb.link\n", cid)
-
-    // // Retrieve a file/directory
-    // res, _ := c.Get(context.Background(), cid)
-
-    // // res is a http.Response with an extra method for reading IPFS UnixFS files!
-    // f, fsys, _ := res.Files()
-    // return ca.SubmitPayForData(ctx, namespace.ID{}, data, 0)
-// }
-
-// write a hook that takes data and puts on Filecoin in function below
-
-// func (ca *CoreAccessor) SubmitTxWithBroadcastMode(
-//  ctx context.Context,
-//  tx Tx,
-//  mode sdktx.BroadcastMode,
-// ) (*TxResponse, error) {
-//  txResp, err := apptypes.BroadcastTx(ctx, ca.coreConn, mode, tx)
-//  if err != nil {
-//      return nil, err
-//  }
-//  return txResp.TxResponse, nil
-// }
+	var txResp *sdktx.BroadcastTxResponse
+	err := ca.withRetry(ctx, func() error {
+		ca.connMu.RLock()
+		conn := ca.coreConn
+		ca.connMu.RUnlock()
+		var broadcastErr error
+		txResp, broadcastErr = apptypes.BroadcastTx(ctx, conn, mode, tx)
+		return broadcastErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return txResp.TxResponse, nil
+}
 
 func (ca *CoreAccessor) Transfer(
-    ctx context.Context,
-    addr AccAddress,
-    amount Int,
-    gasLim uint64,
+	ctx context.Context,
+	addr AccAddress,
+	amount Int,
+	gasLim uint64,
+) (*TxResponse, error) {
+	if amount.IsNil() || amount.Int64() <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	from, err := ca.signerAddress()
+	if err != nil {
+		return nil, err
+	}
+	coins := sdktypes.NewCoins(sdktypes.NewCoin(app.BondDenom, amount))
+	msg := banktypes.NewMsgSend(from, addr, coins)
+	signedTx, err := ca.constructSignedTx(ctx, msg, apptypes.SetGasLimit(gasLim))
+	if err != nil {
+		return nil, err
+	}
+	return ca.SubmitTx(ctx, signedTx)
+}
+
+// FeeOpts controls how TransferWithFee (and similar fee-aware overloads)
+// size the gas limit and gas price of the tx they submit.
+type FeeOpts struct {
+	// Auto, when set, ignores GasLim and GasPrice and sizes the tx from
+	// the configured GasEstimator instead. SubmitPayForDataWithFee is the
+	// exception: it scales GasLim by the estimator's gas adjustment.
+	Auto bool
+	// GasLim is used verbatim when Auto is false.
+	GasLim uint64
+	// GasPrice is used verbatim when Auto is false. SubmitPayForDataWithFee
+	// ignores it.
+	GasPrice sdktypes.Dec
+}
+
+// feeTxOpts resolves opts into the TxBuilderOptions that size a tx's gas
+// limit and, when a gas price is available (either opts.GasPrice or, when
+// opts.Auto is set, the GasEstimator's suggestion), its fee amount priced
+// at gasPrice * gasLim.
+func (ca *CoreAccessor) feeTxOpts(ctx context.Context, msg sdktypes.Msg, opts FeeOpts) ([]apptypes.TxBuilderOption, error) {
+	gasLim, gasPrice := opts.GasLim, opts.GasPrice
+	if opts.Auto {
+		var err error
+		gasLim, gasPrice, err = ca.EstimateGas(ctx, msg)
+		if err != nil {
+			return nil, err
+		}
+	}
+	txOpts := []apptypes.TxBuilderOption{apptypes.SetGasLimit(gasLim)}
+	if !gasPrice.IsNil() {
+		feeAmount := gasPrice.MulInt64(int64(gasLim)).Ceil().TruncateInt()
+		txOpts = append(txOpts, apptypes.SetFeeAmount(sdktypes.NewCoins(sdktypes.NewCoin(app.BondDenom, feeAmount))))
+	}
+	return txOpts, nil
+}
+
+// TransferWithFee behaves like Transfer, but sizes the gas limit and gas
+// price from the GasEstimator when opts.Auto is set instead of requiring
+// the caller to pass raw values.
+func (ca *CoreAccessor) TransferWithFee(
+	ctx context.Context,
+	addr AccAddress,
+	amount Int,
+	opts FeeOpts,
 ) (*TxResponse, error) {
-    if amount.IsNil() || amount.Int64() <= 0 {
-        return nil, ErrInvalidAmount
-    }
-
-    from, err := ca.signer.GetSignerInfo().GetAddress()
-    if err != nil {
-        return nil, err
-    }
-    coins := sdktypes.NewCoins(sdktypes.NewCoin(app.BondDenom, amount))
-    msg := banktypes.NewMsgSend(from, addr, coins)
-    signedTx, err := ca.constructSignedTx(ctx, msg, apptypes.SetGasLimit(gasLim))
-    if err != nil {
-        return nil, err
-    }
-    return ca.SubmitTx(ctx, signedTx)
+	if amount.IsNil() || amount.Int64() <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	from, err := ca.signerAddress()
+	if err != nil {
+		return nil, err
+	}
+	coins := sdktypes.NewCoins(sdktypes.NewCoin(app.BondDenom, amount))
+	msg := banktypes.NewMsgSend(from, addr, coins)
+
+	txOpts, err := ca.feeTxOpts(ctx, msg, opts)
+	if err != nil {
+		return nil, err
+	}
+	signedTx, err := ca.constructSignedTx(ctx, msg, txOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return ca.SubmitTx(ctx, signedTx)
 }
 
 func (ca *CoreAccessor) CancelUnbondingDelegation(
-    ctx context.Context,
-    valAddr ValAddress,
-    amount,
-    height Int,
-    gasLim uint64,
+	ctx context.Context,
+	valAddr ValAddress,
+	amount,
+	height Int,
+	gasLim uint64,
 ) (*TxResponse, error) {
-    if amount.IsNil() || amount.Int64() <= 0 {
-        return nil, ErrInvalidAmount
-    }
-
-    from, err := ca.signer.GetSignerInfo().GetAddress()
-    if err != nil {
-        return nil, err
-    }
-    coins := sdktypes.NewCoin(app.BondDenom, amount)
-    msg := stakingtypes.NewMsgCancelUnbondingDelegation(from, valAddr, height.Int64(), coins)
-    signedTx, err := ca.constructSignedTx(ctx, msg, apptypes.SetGasLimit(gasLim))
-    if err != nil {
-        return nil, err
-    }
-    return ca.SubmitTx(ctx, signedTx)
+	if amount.IsNil() || amount.Int64() <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	from, err := ca.signerAddress()
+	if err != nil {
+		return nil, err
+	}
+	coins := sdktypes.NewCoin(app.BondDenom, amount)
+	msg := stakingtypes.NewMsgCancelUnbondingDelegation(from, valAddr, height.Int64(), coins)
+	signedTx, err := ca.constructSignedTx(ctx, msg, apptypes.SetGasLimit(gasLim))
+	if err != nil {
+		return nil, err
+	}
+	return ca.SubmitTx(ctx, signedTx)
+}
+
+// CancelUnbondingDelegationWithFee behaves like CancelUnbondingDelegation,
+// but sizes the gas limit and gas price from the GasEstimator when
+// opts.Auto is set instead of requiring the caller to pass raw values.
+func (ca *CoreAccessor) CancelUnbondingDelegationWithFee(
+	ctx context.Context,
+	valAddr ValAddress,
+	amount,
+	height Int,
+	opts FeeOpts,
+) (*TxResponse, error) {
+	if amount.IsNil() || amount.Int64() <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	from, err := ca.signerAddress()
+	if err != nil {
+		return nil, err
+	}
+	coins := sdktypes.NewCoin(app.BondDenom, amount)
+	msg := stakingtypes.NewMsgCancelUnbondingDelegation(from, valAddr, height.Int64(), coins)
+
+	txOpts, err := ca.feeTxOpts(ctx, msg, opts)
+	if err != nil {
+		return nil, err
+	}
+	signedTx, err := ca.constructSignedTx(ctx, msg, txOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return ca.SubmitTx(ctx, signedTx)
 }
 
 func (ca *CoreAccessor) BeginRedelegate(
-    ctx context.Context,
-    srcValAddr,
-    dstValAddr ValAddress,
-    amount Int,
-    gasLim uint64,
+	ctx context.Context,
+	srcValAddr,
+	dstValAddr ValAddress,
+	amount Int,
+	gasLim uint64,
 ) (*TxResponse, error) {
-    if amount.IsNil() || amount.Int64() <= 0 {
-        return nil, ErrInvalidAmount
-    }
-
-    from, err := ca.signer.GetSignerInfo().GetAddress()
-    if err != nil {
-        return nil, err
-    }
-    coins := sdktypes.NewCoin(app.BondDenom, amount)
-    msg := stakingtypes.NewMsgBeginRedelegate(from, srcValAddr, dstValAddr, coins)
-    signedTx, err := ca.constructSignedTx(ctx, msg, apptypes.SetGasLimit(gasLim))
-    if err != nil {
-        return nil, err
-    }
-    return ca.SubmitTx(ctx, signedTx)
+	if amount.IsNil() || amount.Int64() <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	from, err := ca.signerAddress()
+	if err != nil {
+		return nil, err
+	}
+	coins := sdktypes.NewCoin(app.BondDenom, amount)
+	msg := stakingtypes.NewMsgBeginRedelegate(from, srcValAddr, dstValAddr, coins)
+	signedTx, err := ca.constructSignedTx(ctx, msg, apptypes.SetGasLimit(gasLim))
+	if err != nil {
+		return nil, err
+	}
+	return ca.SubmitTx(ctx, signedTx)
+}
+
+// BeginRedelegateWithFee behaves like BeginRedelegate, but sizes the gas
+// limit and gas price from the GasEstimator when opts.Auto is set instead
+// of requiring the caller to pass raw values.
+func (ca *CoreAccessor) BeginRedelegateWithFee(
+	ctx context.Context,
+	srcValAddr,
+	dstValAddr ValAddress,
+	amount Int,
+	opts FeeOpts,
+) (*TxResponse, error) {
+	if amount.IsNil() || amount.Int64() <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	from, err := ca.signerAddress()
+	if err != nil {
+		return nil, err
+	}
+	coins := sdktypes.NewCoin(app.BondDenom, amount)
+	msg := stakingtypes.NewMsgBeginRedelegate(from, srcValAddr, dstValAddr, coins)
+
+	txOpts, err := ca.feeTxOpts(ctx, msg, opts)
+	if err != nil {
+		return nil, err
+	}
+	signedTx, err := ca.constructSignedTx(ctx, msg, txOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return ca.SubmitTx(ctx, signedTx)
 }
 
 func (ca *CoreAccessor) Undelegate(
-    ctx context.Context,
-    delAddr ValAddress,
-    amount Int,
-    gasLim uint64,
+	ctx context.Context,
+	delAddr ValAddress,
+	amount Int,
+	gasLim uint64,
 ) (*TxResponse, error) {
-    if amount.IsNil() || amount.Int64() <= 0 {
-        return nil, ErrInvalidAmount
-    }
-
-    from, err := ca.signer.GetSignerInfo().GetAddress()
-    if err != nil {
-        return nil, err
-    }
-    coins := sdktypes.NewCoin(app.BondDenom, amount)
-    msg := stakingtypes.NewMsgUndelegate(from, delAddr, coins)
-    signedTx, err := ca.constructSignedTx(ctx, msg, apptypes.SetGasLimit(gasLim))
-    if err != nil {
-        return nil, err
-    }
-    return ca.SubmitTx(ctx, signedTx)
+	if amount.IsNil() || amount.Int64() <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	from, err := ca.signerAddress()
+	if err != nil {
+		return nil, err
+	}
+	coins := sdktypes.NewCoin(app.BondDenom, amount)
+	msg := stakingtypes.NewMsgUndelegate(from, delAddr, coins)
+	signedTx, err := ca.constructSignedTx(ctx, msg, apptypes.SetGasLimit(gasLim))
+	if err != nil {
+		return nil, err
+	}
+	return ca.SubmitTx(ctx, signedTx)
+}
+
+// UndelegateWithFee behaves like Undelegate, but sizes the gas limit and
+// gas price from the GasEstimator when opts.Auto is set instead of
+// requiring the caller to pass raw values.
+func (ca *CoreAccessor) UndelegateWithFee(
+	ctx context.Context,
+	delAddr ValAddress,
+	amount Int,
+	opts FeeOpts,
+) (*TxResponse, error) {
+	if amount.IsNil() || amount.Int64() <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	from, err := ca.signerAddress()
+	if err != nil {
+		return nil, err
+	}
+	coins := sdktypes.NewCoin(app.BondDenom, amount)
+	msg := stakingtypes.NewMsgUndelegate(from, delAddr, coins)
+
+	txOpts, err := ca.feeTxOpts(ctx, msg, opts)
+	if err != nil {
+		return nil, err
+	}
+	signedTx, err := ca.constructSignedTx(ctx, msg, txOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return ca.SubmitTx(ctx, signedTx)
 }
 
 func (ca *CoreAccessor) Delegate(
-    ctx context.Context,
-    delAddr ValAddress,
-    amount Int,
-    gasLim uint64,
+	ctx context.Context,
+	delAddr ValAddress,
+	amount Int,
+	gasLim uint64,
 ) (*TxResponse, error) {
-    if amount.IsNil() || amount.Int64() <= 0 {
-        return nil, ErrInvalidAmount
-    }
-
-    from, err := ca.signer.GetSignerInfo().GetAddress()
-    if err != nil {
-        return nil, err
-    }
-    coins := sdktypes.NewCoin(app.BondDenom, amount)
-    msg := stakingtypes.NewMsgDelegate(from, delAddr, coins)
-    signedTx, err := ca.constructSignedTx(ctx, msg, apptypes.SetGasLimit(gasLim))
-    if err != nil {
-        return nil, err
-    }
-    return ca.SubmitTx(ctx, signedTx)
+	if amount.IsNil() || amount.Int64() <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	from, err := ca.signerAddress()
+	if err != nil {
+		return nil, err
+	}
+	coins := sdktypes.NewCoin(app.BondDenom, amount)
+	msg := stakingtypes.NewMsgDelegate(from, delAddr, coins)
+	signedTx, err := ca.constructSignedTx(ctx, msg, apptypes.SetGasLimit(gasLim))
+	if err != nil {
+		return nil, err
+	}
+	return ca.SubmitTx(ctx, signedTx)
+}
+
+// DelegateWithFee behaves like Delegate, but sizes the gas limit and gas
+// price from the GasEstimator when opts.Auto is set instead of requiring
+// the caller to pass raw values.
+func (ca *CoreAccessor) DelegateWithFee(
+	ctx context.Context,
+	delAddr ValAddress,
+	amount Int,
+	opts FeeOpts,
+) (*TxResponse, error) {
+	if amount.IsNil() || amount.Int64() <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	from, err := ca.signerAddress()
+	if err != nil {
+		return nil, err
+	}
+	coins := sdktypes.NewCoin(app.BondDenom, amount)
+	msg := stakingtypes.NewMsgDelegate(from, delAddr, coins)
+
+	txOpts, err := ca.feeTxOpts(ctx, msg, opts)
+	if err != nil {
+		return nil, err
+	}
+	signedTx, err := ca.constructSignedTx(ctx, msg, txOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return ca.SubmitTx(ctx, signedTx)
 }
 
 func (ca *CoreAccessor) QueryDelegation(
-    ctx context.Context,
-    valAddr ValAddress,
+	ctx context.Context,
+	valAddr ValAddress,
 ) (*stakingtypes.QueryDelegationResponse, error) {
-    delAddr, err := ca.signer.GetSignerInfo().GetAddress()
-    if err != nil {
-        return nil, err
-    }
-    return ca.stakingCli.Delegation(ctx, &stakingtypes.QueryDelegationRequest{
-        DelegatorAddr: delAddr.String(),
-        ValidatorAddr: valAddr.String(),
-    })
+	delAddr, err := ca.signerAddress()
+	if err != nil {
+		return nil, err
+	}
+	var resp *stakingtypes.QueryDelegationResponse
+	err = ca.withRetry(ctx, func() error {
+		ca.connMu.RLock()
+		stakingCli := ca.stakingCli
+		ca.connMu.RUnlock()
+		var queryErr error
+		resp, queryErr = stakingCli.Delegation(ctx, &stakingtypes.QueryDelegationRequest{
+			DelegatorAddr: delAddr.String(),
+			ValidatorAddr: valAddr.String(),
+		})
+		return queryErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
 }
 
 func (ca *CoreAccessor) QueryUnbonding(
-    ctx context.Context,
-    valAddr ValAddress,
+	ctx context.Context,
+	valAddr ValAddress,
 ) (*stakingtypes.QueryUnbondingDelegationResponse, error) {
-    delAddr, err := ca.signer.GetSignerInfo().GetAddress()
-    if err != nil {
-        return nil, err
-    }
-    return ca.stakingCli.UnbondingDelegation(ctx, &stakingtypes.QueryUnbondingDelegationRequest{
-        DelegatorAddr: delAddr.String(),
-        ValidatorAddr: valAddr.String(),
-    })
+	delAddr, err := ca.signerAddress()
+	if err != nil {
+		return nil, err
+	}
+	var resp *stakingtypes.QueryUnbondingDelegationResponse
+	err = ca.withRetry(ctx, func() error {
+		ca.connMu.RLock()
+		stakingCli := ca.stakingCli
+		ca.connMu.RUnlock()
+		var queryErr error
+		resp, queryErr = stakingCli.UnbondingDelegation(ctx, &stakingtypes.QueryUnbondingDelegationRequest{
+			DelegatorAddr: delAddr.String(),
+			ValidatorAddr: valAddr.String(),
+		})
+		return queryErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
 }
+
 func (ca *CoreAccessor) QueryRedelegations(
-    ctx context.Context,
-    srcValAddr,
-    dstValAddr ValAddress,
+	ctx context.Context,
+	srcValAddr,
+	dstValAddr ValAddress,
 ) (*stakingtypes.QueryRedelegationsResponse, error) {
-    delAddr, err := ca.signer.GetSignerInfo().GetAddress()
-    if err != nil {
-        return nil, err
-    }
-    return ca.stakingCli.Redelegations(ctx, &stakingtypes.QueryRedelegationsRequest{
-        DelegatorAddr:    delAddr.String(),
-        SrcValidatorAddr: srcValAddr.String(),
-        DstValidatorAddr: dstValAddr.String(),
-    })
+	delAddr, err := ca.signerAddress()
+	if err != nil {
+		return nil, err
+	}
+	var resp *stakingtypes.QueryRedelegationsResponse
+	err = ca.withRetry(ctx, func() error {
+		ca.connMu.RLock()
+		stakingCli := ca.stakingCli
+		ca.connMu.RUnlock()
+		var queryErr error
+		resp, queryErr = stakingCli.Redelegations(ctx, &stakingtypes.QueryRedelegationsRequest{
+			DelegatorAddr:    delAddr.String(),
+			SrcValidatorAddr: srcValAddr.String(),
+			DstValidatorAddr: dstValAddr.String(),
+		})
+		return queryErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
 }
 
 func (ca *CoreAccessor) IsStopped() bool {
-    return ca.ctx.Err() != nil
-}
\ No newline at end of file
+	return ca.ctx.Err() != nil
+}
@@ -0,0 +1,173 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	signingtypes "github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"google.golang.org/grpc"
+
+	apptypes "github.com/celestiaorg/celestia-app/x/payment/types"
+)
+
+// directSignMode is the sign mode DirectTxBuilder asks callers to sign
+// over. Unlike SIGN_MODE_DIRECT, amino JSON sign bytes don't embed the
+// signer's public key, so they can be computed before the pubkey is known
+// — which is exactly the RemoteSigner/OfflineSigner situation, where the
+// pubkey only arrives alongside the detached signature.
+const directSignMode = signingtypes.SignMode_SIGN_MODE_LEGACY_AMINO_JSON
+
+// directUnsignedTx is the wire envelope DirectTxBuilder hands to a
+// RemoteSigner or OfflineSigner: the sign bytes to sign, plus everything
+// needed to splice a returned signature back into a broadcastable tx.
+type directUnsignedTx struct {
+	SignBytes     []byte `json:"sign_bytes"`
+	TxBytes       []byte `json:"tx_bytes"` // unsigned tx, encoded by txConfig.TxEncoder
+	AccountNumber uint64 `json:"account_number"`
+	Sequence      uint64 `json:"sequence"`
+}
+
+// DirectTxBuilder is a reference UnsignedTxBuilder built directly on the
+// Cosmos SDK's own tx-builder and sign-mode machinery, independent of any
+// Signer's private key material. It is what makes RemoteSigner and
+// OfflineSigner usable without every caller having to write their own
+// unsigned-tx/signature-splicing code first.
+type DirectTxBuilder struct {
+	chainID string
+	txCfg   sdkclient.TxConfig
+}
+
+// NewDirectTxBuilder constructs a DirectTxBuilder that builds txs for
+// chainID.
+func NewDirectTxBuilder(chainID string) *DirectTxBuilder {
+	// SetSignature decodes the unsigned tx back from its wire form, which
+	// resolves each message's type URL against this registry.
+	registry := codectypes.NewInterfaceRegistry()
+	banktypes.RegisterInterfaces(registry)
+	stakingtypes.RegisterInterfaces(registry)
+	govtypes.RegisterInterfaces(registry)
+	RegisterGovInterfaces(registry)
+	protoCodec := codec.NewProtoCodec(registry)
+	return &DirectTxBuilder{
+		chainID: chainID,
+		txCfg:   authtx.NewTxConfig(protoCodec, []signingtypes.SignMode{directSignMode}),
+	}
+}
+
+// BuildUnsigned returns the JSON-encoded directUnsignedTx carrying the
+// amino JSON sign bytes for msg, ready for a RemoteSigner or OfflineSigner
+// to have them signed out-of-process.
+func (b *DirectTxBuilder) BuildUnsigned(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	msg sdktypes.Msg,
+	opts ...apptypes.TxBuilderOption,
+) ([]byte, error) {
+	signers := msg.GetSigners()
+	if len(signers) != 1 {
+		return nil, fmt.Errorf("state: DirectTxBuilder requires exactly one signer, got %d", len(signers))
+	}
+
+	accNum, seq, err := queryAccount(ctx, conn, signers[0])
+	if err != nil {
+		return nil, err
+	}
+
+	txBuilder := b.txCfg.NewTxBuilder()
+	if err := txBuilder.SetMsgs(msg); err != nil {
+		return nil, fmt.Errorf("state: setting tx messages: %w", err)
+	}
+	for _, opt := range opts {
+		opt(txBuilder)
+	}
+
+	signBytes, err := b.txCfg.SignModeHandler().GetSignBytes(
+		directSignMode,
+		authsigning.SignerData{
+			ChainID:       b.chainID,
+			AccountNumber: accNum,
+			Sequence:      seq,
+		},
+		txBuilder.GetTx(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("state: computing sign bytes: %w", err)
+	}
+
+	txBytes, err := b.txCfg.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		return nil, fmt.Errorf("state: encoding unsigned tx: %w", err)
+	}
+
+	return json.Marshal(directUnsignedTx{
+		SignBytes:     signBytes,
+		TxBytes:       txBytes,
+		AccountNumber: accNum,
+		Sequence:      seq,
+	})
+}
+
+// SetSignature splices sig and pubKey into the unsigned tx previously
+// returned by BuildUnsigned, returning the wire-encoded, broadcastable
+// signed tx.
+func (b *DirectTxBuilder) SetSignature(unsignedTx, sig, pubKey []byte) ([]byte, error) {
+	var env directUnsignedTx
+	if err := json.Unmarshal(unsignedTx, &env); err != nil {
+		return nil, fmt.Errorf("state: decoding unsigned tx: %w", err)
+	}
+
+	tx, err := b.txCfg.TxDecoder()(env.TxBytes)
+	if err != nil {
+		return nil, fmt.Errorf("state: decoding unsigned tx bytes: %w", err)
+	}
+	txBuilder, err := b.txCfg.WrapTxBuilder(tx)
+	if err != nil {
+		return nil, fmt.Errorf("state: wrapping unsigned tx: %w", err)
+	}
+
+	sigV2 := signingtypes.SignatureV2{
+		PubKey: &secp256k1.PubKey{Key: pubKey},
+		Data: &signingtypes.SingleSignatureData{
+			SignMode:  directSignMode,
+			Signature: sig,
+		},
+		Sequence: env.Sequence,
+	}
+	if err := txBuilder.SetSignatures(sigV2); err != nil {
+		return nil, fmt.Errorf("state: setting signature: %w", err)
+	}
+
+	return b.txCfg.TxEncoder()(txBuilder.GetTx())
+}
+
+// queryAccount looks up addr's account number and sequence over conn, the
+// same bookkeeping apptypes.KeyringSigner.QueryAccountNumber performs
+// internally before signing. It reads the raw BaseAccount bytes directly
+// out of the QueryAccountResponse rather than unpacking through an
+// interface registry, since DirectTxBuilder registers only the message
+// types it signs, not any Cosmos SDK account types.
+func queryAccount(ctx context.Context, conn *grpc.ClientConn, addr sdktypes.AccAddress) (accNum, sequence uint64, err error) {
+	accCli := authtypes.NewQueryClient(conn)
+	resp, err := accCli.Account(ctx, &authtypes.QueryAccountRequest{Address: addr.String()})
+	if err != nil {
+		return 0, 0, fmt.Errorf("state: querying account: %w", err)
+	}
+
+	var baseAcc authtypes.BaseAccount
+	if err := baseAcc.Unmarshal(resp.Account.Value); err != nil {
+		return 0, 0, fmt.Errorf("state: unpacking account: %w", err)
+	}
+	return baseAcc.AccountNumber, baseAcc.Sequence, nil
+}
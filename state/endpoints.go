@@ -0,0 +1,218 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/tendermint/tendermint/rpc/client/http"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// healthCheckInterval is how often the supervising goroutine probes the
+// active endpoint's liveness.
+const healthCheckInterval = 15 * time.Second
+
+// failoverInitialBackoff and failoverMaxBackoff bound the exponential
+// backoff used while failing over to the next endpoint in the pool.
+const (
+	failoverInitialBackoff = time.Second
+	failoverMaxBackoff     = 30 * time.Second
+)
+
+// Endpoint identifies a celestia-core node CoreAccessor can dial.
+type Endpoint struct {
+	CoreIP   string
+	RPCPort  string
+	GRPCPort string
+}
+
+func (e Endpoint) grpcAddr() string {
+	return fmt.Sprintf("%s:%s", e.CoreIP, e.GRPCPort)
+}
+
+func (e Endpoint) rpcAddr() string {
+	return fmt.Sprintf("http://%s:%s", e.CoreIP, e.RPCPort)
+}
+
+// ConnectionState reports which endpoint CoreAccessor is currently
+// connected to and the last error seen against it, if any.
+type ConnectionState struct {
+	Endpoint  Endpoint
+	LastError error
+}
+
+// ConnectionState returns the currently active endpoint and the last error
+// observed against the connection.
+func (ca *CoreAccessor) ConnectionState() ConnectionState {
+	ca.connMu.RLock()
+	defer ca.connMu.RUnlock()
+
+	var ep Endpoint
+	if ca.activeIdx < len(ca.endpoints) {
+		ep = ca.endpoints[ca.activeIdx]
+	}
+	return ConnectionState{Endpoint: ep, LastError: ca.lastErr}
+}
+
+// dial tries each endpoint in ca.endpoints once, starting at startIdx and
+// wrapping around, and swaps in the first one it can successfully connect
+// to. It closes any previously active connection once the new one is in
+// place.
+func (ca *CoreAccessor) dial(ctx context.Context, startIdx int) error {
+	n := len(ca.endpoints)
+	var lastErr error
+	for i := 0; i < n; i++ {
+		idx := (startIdx + i) % n
+		ep := ca.endpoints[idx]
+
+		conn, err := grpc.DialContext(ctx, ep.grpcAddr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		rpcCli, err := http.New(ep.rpcAddr(), "/websocket")
+		if err != nil {
+			conn.Close()
+			lastErr = err
+			continue
+		}
+
+		ca.connMu.Lock()
+		old := ca.coreConn
+		ca.coreConn = conn
+		ca.queryCli = banktypes.NewQueryClient(conn)
+		ca.stakingCli = stakingtypes.NewQueryClient(conn)
+		ca.govCli = govtypes.NewQueryClient(conn)
+		ca.rpcCli = rpcCli
+		ca.healthCli = rpcCli
+		ca.txServiceCli = sdktx.NewServiceClient(conn)
+		ca.gasEstimator = NewGasEstimator(rpcCli, ca.gasEstimatorOpts...)
+		ca.activeIdx = idx
+		ca.lastErr = nil
+		ca.connMu.Unlock()
+
+		if old != nil {
+			old.Close()
+		}
+		return nil
+	}
+	return fmt.Errorf("core-access: failed to dial any endpoint: %w", lastErr)
+}
+
+// failover repeatedly tries to reconnect starting at the endpoint after the
+// currently active one, backing off exponentially between full passes over
+// the pool, until ctx is done or a connection succeeds.
+func (ca *CoreAccessor) failover(ctx context.Context) {
+	ca.connMu.RLock()
+	startIdx := (ca.activeIdx + 1) % len(ca.endpoints)
+	ca.connMu.RUnlock()
+
+	backoff := failoverInitialBackoff
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if err := ca.dial(ctx, startIdx); err != nil {
+			ca.connMu.Lock()
+			ca.lastErr = err
+			ca.connMu.Unlock()
+			log.Errorw("core-access: failover attempt failed", "err", err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if backoff *= 2; backoff > failoverMaxBackoff {
+				backoff = failoverMaxBackoff
+			}
+			continue
+		}
+
+		state := ca.ConnectionState()
+		log.Infow("core-access: failed over to new endpoint", "endpoint", state.Endpoint)
+		return
+	}
+}
+
+// superviseConnection periodically checks the active endpoint's liveness
+// and triggers failover on error, until ctx is done.
+func (ca *CoreAccessor) superviseConnection(ctx context.Context) {
+	defer close(ca.superviseDone)
+
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ca.connMu.RLock()
+			healthCli := ca.healthCli
+			ca.connMu.RUnlock()
+			if healthCli == nil {
+				continue
+			}
+			if _, err := healthCli.Status(ctx); err != nil {
+				log.Warnw("core-access: active endpoint failed health check", "err", err)
+				ca.failover(ctx)
+			}
+		}
+	}
+}
+
+// isTransientRPCError reports whether err is a gRPC or Tendermint RPC HTTP
+// error that a retry against a fresh endpoint is likely to resolve.
+func isTransientRPCError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.Unavailable, codes.Canceled:
+			return true
+		}
+	}
+
+	// ABCIQueryWithOptions goes over the Tendermint RPC HTTP client, not
+	// gRPC, so its errors never carry a gRPC status; classify the
+	// connection-level failures directly instead.
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// withRetry runs fn once, and if it fails with a transient RPC error,
+// fails over to the next endpoint in the pool and retries fn exactly once
+// more against the fresh connection.
+func (ca *CoreAccessor) withRetry(ctx context.Context, fn func() error) error {
+	err := fn()
+	if err == nil || !isTransientRPCError(err) {
+		return err
+	}
+
+	log.Warnw("core-access: transient RPC error, retrying against a fresh endpoint", "err", err)
+	ca.connMu.RLock()
+	nextIdx := (ca.activeIdx + 1) % len(ca.endpoints)
+	ca.connMu.RUnlock()
+	if dialErr := ca.dial(ctx, nextIdx); dialErr != nil {
+		return err
+	}
+	return fn()
+}
@@ -0,0 +1,184 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// ErrGasEstimatorNotConfigured is returned by CoreAccessor fee methods when
+// Start has not yet run (the GasEstimator is built against the dialed core
+// RPC client).
+var ErrGasEstimatorNotConfigured = errors.New("state: gas estimator not yet started")
+
+// gasRPCClient is the subset of the Tendermint RPC client the GasEstimator
+// needs to sample block gas utilization.
+type gasRPCClient interface {
+	Status(ctx context.Context) (*coretypes.ResultStatus, error)
+	BlockResults(ctx context.Context, height *int64) (*coretypes.ResultBlockResults, error)
+	ConsensusParams(ctx context.Context, height *int64) (*coretypes.ResultConsensusParams, error)
+}
+
+// GasEstimator maintains a rolling window of recent block_gas_used/max_gas
+// ratios and derives a suggested minimum gas price from it, following the
+// Cosmos SDK fee-market approach: the price biases upward when the window
+// average exceeds the target utilization and decays toward a floor when
+// below it.
+type GasEstimator struct {
+	rpcCli gasRPCClient
+
+	mu         sync.Mutex
+	window     []sdktypes.Dec
+	windowSize int
+	// lastHeight is the height of the most recently sampled block, so
+	// Refresh can skip re-sampling (and re-counting) a height it has
+	// already folded into window.
+	lastHeight int64
+
+	targetUtilization sdktypes.Dec
+	adjustmentStep    sdktypes.Dec
+	floorGasPrice     sdktypes.Dec
+	gasPrice          sdktypes.Dec
+
+	// gasAdjustment multiplies a simulated tx's GasUsed to size the
+	// recommended gas limit, leaving headroom for estimation error.
+	gasAdjustment float64
+}
+
+// GasEstimatorOption configures a GasEstimator at construction time.
+type GasEstimatorOption func(*GasEstimator)
+
+// WithWindowSize sets how many recent blocks are averaged when computing
+// utilization. Defaults to 100.
+func WithWindowSize(n int) GasEstimatorOption {
+	return func(ge *GasEstimator) { ge.windowSize = n }
+}
+
+// WithTargetUtilization sets the block_gas_used/max_gas ratio the estimator
+// biases the gas price toward. Defaults to 0.5.
+func WithTargetUtilization(target sdktypes.Dec) GasEstimatorOption {
+	return func(ge *GasEstimator) { ge.targetUtilization = target }
+}
+
+// WithFloorGasPrice sets the minimum gas price the estimator will ever
+// suggest. Defaults to 0.001.
+func WithFloorGasPrice(floor sdktypes.Dec) GasEstimatorOption {
+	return func(ge *GasEstimator) { ge.floorGasPrice = floor }
+}
+
+// WithGasAdjustment sets the multiplier applied to a simulated tx's
+// GasUsed when sizing a recommended gas limit. Defaults to 1.3.
+func WithGasAdjustment(adj float64) GasEstimatorOption {
+	return func(ge *GasEstimator) { ge.gasAdjustment = adj }
+}
+
+// NewGasEstimator constructs a GasEstimator sampling block gas utilization
+// from rpcCli.
+func NewGasEstimator(rpcCli gasRPCClient, opts ...GasEstimatorOption) *GasEstimator {
+	ge := &GasEstimator{
+		rpcCli:            rpcCli,
+		windowSize:        100,
+		targetUtilization: sdktypes.NewDecWithPrec(5, 1),
+		adjustmentStep:    sdktypes.NewDecWithPrec(1, 2),
+		floorGasPrice:     sdktypes.NewDecWithPrec(1, 3),
+		gasAdjustment:     1.3,
+	}
+	for _, opt := range opts {
+		opt(ge)
+	}
+	ge.gasPrice = ge.floorGasPrice
+	return ge
+}
+
+// blockUtilization fetches height's gas_used/max_gas ratio.
+func (ge *GasEstimator) blockUtilization(ctx context.Context, height int64) (sdktypes.Dec, error) {
+	results, err := ge.rpcCli.BlockResults(ctx, &height)
+	if err != nil {
+		return sdktypes.Dec{}, err
+	}
+	var gasUsed int64
+	for _, txResult := range results.TxsResults {
+		gasUsed += txResult.GasUsed
+	}
+
+	params, err := ge.rpcCli.ConsensusParams(ctx, &height)
+	if err != nil {
+		return sdktypes.Dec{}, err
+	}
+	maxGas := params.ConsensusParams.Block.MaxGas
+	if maxGas <= 0 {
+		return sdktypes.ZeroDec(), nil
+	}
+	return sdktypes.NewDec(gasUsed).QuoInt64(maxGas), nil
+}
+
+// Refresh samples the latest block's gas utilization, folds it into the
+// rolling window, and re-derives the suggested gas price from the window
+// average. It is a no-op if the latest height has already been sampled,
+// so calling it more than once per block (e.g. several callers hitting
+// SuggestGasPrice within the same block) doesn't push duplicate samples
+// into the window.
+func (ge *GasEstimator) Refresh(ctx context.Context) error {
+	status, err := ge.rpcCli.Status(ctx)
+	if err != nil {
+		return err
+	}
+	height := status.SyncInfo.LatestBlockHeight
+
+	ge.mu.Lock()
+	if height <= ge.lastHeight {
+		ge.mu.Unlock()
+		return nil
+	}
+	ge.mu.Unlock()
+
+	utilization, err := ge.blockUtilization(ctx, height)
+	if err != nil {
+		return err
+	}
+
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+	if height <= ge.lastHeight {
+		// lost the race against a concurrent Refresh that already
+		// sampled this height.
+		return nil
+	}
+	ge.lastHeight = height
+
+	ge.window = append(ge.window, utilization)
+	if len(ge.window) > ge.windowSize {
+		ge.window = ge.window[len(ge.window)-ge.windowSize:]
+	}
+
+	avg := sdktypes.ZeroDec()
+	for _, u := range ge.window {
+		avg = avg.Add(u)
+	}
+	avg = avg.QuoInt64(int64(len(ge.window)))
+
+	switch {
+	case avg.GT(ge.targetUtilization):
+		ge.gasPrice = ge.gasPrice.Mul(sdktypes.OneDec().Add(ge.adjustmentStep))
+	case avg.LT(ge.targetUtilization):
+		ge.gasPrice = ge.gasPrice.Mul(sdktypes.OneDec().Sub(ge.adjustmentStep))
+		if ge.gasPrice.LT(ge.floorGasPrice) {
+			ge.gasPrice = ge.floorGasPrice
+		}
+	}
+	return nil
+}
+
+// SuggestGasPrice refreshes the rolling window from the latest block and
+// returns the resulting suggested minimum gas price.
+func (ge *GasEstimator) SuggestGasPrice(ctx context.Context) (sdktypes.Dec, error) {
+	if err := ge.Refresh(ctx); err != nil {
+		return sdktypes.Dec{}, err
+	}
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+	return ge.gasPrice, nil
+}
@@ -0,0 +1,175 @@
+package state
+
+import (
+	"context"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	paramproposal "github.com/cosmos/cosmos-sdk/x/params/types/proposal"
+
+	apptypes "github.com/celestiaorg/celestia-app/x/payment/types"
+)
+
+// RegisterGovInterfaces registers the proposal Content types CoreAccessor's
+// governance API accepts (TextProposal and ParameterChangeProposal) with the
+// app's interface registry. The node's app wiring must call this (or
+// register the same implementations itself) before SubmitProposal can
+// encode either content type into a MsgSubmitProposal.
+func RegisterGovInterfaces(registry codectypes.InterfaceRegistry) {
+	registry.RegisterImplementations(
+		(*govtypes.Content)(nil),
+		&govtypes.TextProposal{},
+		&paramproposal.ParameterChangeProposal{},
+	)
+}
+
+// SubmitProposal submits a governance proposal with the given content and
+// initial deposit. content is typically a *govtypes.TextProposal or a
+// *paramproposal.ParameterChangeProposal.
+func (ca *CoreAccessor) SubmitProposal(
+	ctx context.Context,
+	content govtypes.Content,
+	initialDeposit sdktypes.Coins,
+	gasLim uint64,
+) (*TxResponse, error) {
+	from, err := ca.signerAddress()
+	if err != nil {
+		return nil, err
+	}
+	msg, err := govtypes.NewMsgSubmitProposal(content, initialDeposit, from)
+	if err != nil {
+		return nil, err
+	}
+	signedTx, err := ca.constructSignedTx(ctx, msg, apptypes.SetGasLimit(gasLim))
+	if err != nil {
+		return nil, err
+	}
+	return ca.SubmitTx(ctx, signedTx)
+}
+
+// Vote casts a vote on the given proposal on behalf of the signer.
+func (ca *CoreAccessor) Vote(
+	ctx context.Context,
+	proposalID uint64,
+	option govtypes.VoteOption,
+	gasLim uint64,
+) (*TxResponse, error) {
+	from, err := ca.signerAddress()
+	if err != nil {
+		return nil, err
+	}
+	msg := govtypes.NewMsgVote(from, proposalID, option)
+	signedTx, err := ca.constructSignedTx(ctx, msg, apptypes.SetGasLimit(gasLim))
+	if err != nil {
+		return nil, err
+	}
+	return ca.SubmitTx(ctx, signedTx)
+}
+
+// Deposit adds to a proposal's deposit on behalf of the signer.
+func (ca *CoreAccessor) Deposit(
+	ctx context.Context,
+	proposalID uint64,
+	amount sdktypes.Coins,
+	gasLim uint64,
+) (*TxResponse, error) {
+	from, err := ca.signerAddress()
+	if err != nil {
+		return nil, err
+	}
+	msg := govtypes.NewMsgDeposit(from, proposalID, amount)
+	signedTx, err := ca.constructSignedTx(ctx, msg, apptypes.SetGasLimit(gasLim))
+	if err != nil {
+		return nil, err
+	}
+	return ca.SubmitTx(ctx, signedTx)
+}
+
+// QueryProposal returns the proposal with the given ID.
+func (ca *CoreAccessor) QueryProposal(ctx context.Context, proposalID uint64) (*govtypes.QueryProposalResponse, error) {
+	var resp *govtypes.QueryProposalResponse
+	err := ca.withRetry(ctx, func() error {
+		ca.connMu.RLock()
+		govCli := ca.govCli
+		ca.connMu.RUnlock()
+		var queryErr error
+		resp, queryErr = govCli.Proposal(ctx, &govtypes.QueryProposalRequest{ProposalId: proposalID})
+		return queryErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// QueryProposals returns proposals matching the given status, voter, and
+// depositor filters. Any of voter or depositor may be nil to skip that
+// filter.
+func (ca *CoreAccessor) QueryProposals(
+	ctx context.Context,
+	status govtypes.ProposalStatus,
+	voter, depositor AccAddress,
+) (*govtypes.QueryProposalsResponse, error) {
+	req := &govtypes.QueryProposalsRequest{ProposalStatus: status}
+	if voter != nil {
+		req.Voter = voter.String()
+	}
+	if depositor != nil {
+		req.Depositor = depositor.String()
+	}
+	var resp *govtypes.QueryProposalsResponse
+	err := ca.withRetry(ctx, func() error {
+		ca.connMu.RLock()
+		govCli := ca.govCli
+		ca.connMu.RUnlock()
+		var queryErr error
+		resp, queryErr = govCli.Proposals(ctx, req)
+		return queryErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// QueryVote returns voter's vote on the given proposal.
+func (ca *CoreAccessor) QueryVote(
+	ctx context.Context,
+	proposalID uint64,
+	voter AccAddress,
+) (*govtypes.QueryVoteResponse, error) {
+	var resp *govtypes.QueryVoteResponse
+	err := ca.withRetry(ctx, func() error {
+		ca.connMu.RLock()
+		govCli := ca.govCli
+		ca.connMu.RUnlock()
+		var queryErr error
+		resp, queryErr = govCli.Vote(ctx, &govtypes.QueryVoteRequest{
+			ProposalId: proposalID,
+			Voter:      voter.String(),
+		})
+		return queryErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// QueryTally returns the current tally result for the given proposal.
+func (ca *CoreAccessor) QueryTally(ctx context.Context, proposalID uint64) (*govtypes.QueryTallyResultResponse, error) {
+	var resp *govtypes.QueryTallyResultResponse
+	err := ca.withRetry(ctx, func() error {
+		ca.connMu.RLock()
+		govCli := ca.govCli
+		ca.connMu.RUnlock()
+		var queryErr error
+		resp, queryErr = govCli.TallyResult(ctx, &govtypes.QueryTallyResultRequest{ProposalId: proposalID})
+		return queryErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
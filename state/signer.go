@@ -0,0 +1,198 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"google.golang.org/grpc"
+
+	apptypes "github.com/celestiaorg/celestia-app/x/payment/types"
+)
+
+// Signer abstracts how CoreAccessor turns a message into a broadcastable,
+// signed tx. This lets the signing key live in-process (KeyringSigner),
+// behind a remote signing daemon (RemoteSigner), or be supplied entirely
+// out-of-band (OfflineSigner), so validators can keep keys in HSMs or on
+// separate, air-gapped hosts.
+type Signer interface {
+	// Address returns the account address this signer signs for.
+	Address() (Address, error)
+	// Sign builds a tx carrying msg, has it signed, and returns the
+	// wire-encoded tx bytes ready to broadcast over conn.
+	Sign(ctx context.Context, conn *grpc.ClientConn, msg sdktypes.Msg, opts ...apptypes.TxBuilderOption) ([]byte, error)
+}
+
+// KeyringSigner is the default Signer, backed by a local keyring holding
+// the account's private key.
+type KeyringSigner struct {
+	signer *apptypes.KeyringSigner
+}
+
+// NewKeyringSigner wraps signer as a Signer.
+func NewKeyringSigner(signer *apptypes.KeyringSigner) *KeyringSigner {
+	return &KeyringSigner{signer: signer}
+}
+
+func (s *KeyringSigner) Address() (Address, error) {
+	return s.signer.GetSignerInfo().GetAddress()
+}
+
+func (s *KeyringSigner) Sign(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	msg sdktypes.Msg,
+	opts ...apptypes.TxBuilderOption,
+) ([]byte, error) {
+	// should be called first in order to make a valid tx
+	if err := s.signer.QueryAccountNumber(ctx, conn); err != nil {
+		return nil, err
+	}
+	tx, err := s.signer.BuildSignedTx(s.signer.NewTxBuilder(opts...), msg)
+	if err != nil {
+		return nil, err
+	}
+	return s.signer.EncodeTx(tx)
+}
+
+// UnsignedTxBuilder builds the unsigned portion of a tx and splices a
+// signature and public key obtained elsewhere back into it. RemoteSigner
+// and OfflineSigner are built against this narrower interface so that
+// neither ever needs access to private key material.
+//
+// DirectTxBuilder is the reference implementation; callers with unusual
+// tx-building requirements can supply their own.
+type UnsignedTxBuilder interface {
+	// BuildUnsigned returns an encoding of an unsigned tx carrying msg, in
+	// the form this builder's SetSignature accepts back (DirectTxBuilder
+	// returns a JSON envelope holding the sign bytes and the unsigned tx).
+	BuildUnsigned(ctx context.Context, conn *grpc.ClientConn, msg sdktypes.Msg, opts ...apptypes.TxBuilderOption) ([]byte, error)
+	// SetSignature splices sig and pubKey into the unsigned tx previously
+	// returned by BuildUnsigned, returning the wire-encoded signed tx.
+	SetSignature(unsignedTx, sig, pubKey []byte) ([]byte, error)
+}
+
+// RemoteSigner delegates signing to an external daemon over a small
+// JSON-RPC protocol: the unsigned tx bytes go out, a signature and public
+// key come back. This lets validators keep keys in an HSM or on a
+// separate host instead of this process's keyring.
+type RemoteSigner struct {
+	addr     Address
+	builder  UnsignedTxBuilder
+	endpoint string
+	httpCli  *http.Client
+}
+
+// NewRemoteSigner constructs a RemoteSigner that signs for addr by calling
+// the signing daemon at endpoint. builder must be a caller-supplied
+// UnsignedTxBuilder; see that interface's doc comment.
+func NewRemoteSigner(addr Address, builder UnsignedTxBuilder, endpoint string) *RemoteSigner {
+	return &RemoteSigner{
+		addr:     addr,
+		builder:  builder,
+		endpoint: endpoint,
+		httpCli:  &http.Client{},
+	}
+}
+
+func (s *RemoteSigner) Address() (Address, error) {
+	return s.addr, nil
+}
+
+type remoteSignRequest struct {
+	UnsignedTx []byte `json:"unsigned_tx"`
+}
+
+type remoteSignResponse struct {
+	Signature []byte `json:"signature"`
+	PubKey    []byte `json:"pub_key"`
+}
+
+func (s *RemoteSigner) Sign(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	msg sdktypes.Msg,
+	opts ...apptypes.TxBuilderOption,
+) ([]byte, error) {
+	unsignedTx, err := s.builder.BuildUnsigned(ctx, conn, msg, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(remoteSignRequest{UnsignedTx: unsignedTx})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpCli.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("state: remote signer request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("state: remote signer returned status %d", resp.StatusCode)
+	}
+
+	var signResp remoteSignResponse
+	if err := json.NewDecoder(resp.Body).Decode(&signResp); err != nil {
+		return nil, err
+	}
+	return s.builder.SetSignature(unsignedTx, signResp.Signature, signResp.PubKey)
+}
+
+// OfflineSigner writes unsigned tx bytes to out and blocks on in for the
+// matching signed tx bytes, for broadcast-only workflows where signing
+// happens on a separate, air-gapped machine.
+type OfflineSigner struct {
+	addr    Address
+	builder UnsignedTxBuilder
+	out     io.Writer
+	in      io.Reader
+}
+
+// NewOfflineSigner constructs an OfflineSigner that signs for addr by
+// writing the unsigned tx to out and reading the signed tx back from in.
+// builder must be a caller-supplied UnsignedTxBuilder; see that
+// interface's doc comment.
+func NewOfflineSigner(addr Address, builder UnsignedTxBuilder, out io.Writer, in io.Reader) *OfflineSigner {
+	return &OfflineSigner{
+		addr:    addr,
+		builder: builder,
+		out:     out,
+		in:      in,
+	}
+}
+
+func (s *OfflineSigner) Address() (Address, error) {
+	return s.addr, nil
+}
+
+func (s *OfflineSigner) Sign(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	msg sdktypes.Msg,
+	opts ...apptypes.TxBuilderOption,
+) ([]byte, error) {
+	unsignedTx, err := s.builder.BuildUnsigned(ctx, conn, msg, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.out.Write(append(unsignedTx, '\n')); err != nil {
+		return nil, fmt.Errorf("state: writing unsigned tx: %w", err)
+	}
+
+	signedTx, err := io.ReadAll(s.in)
+	if err != nil {
+		return nil, fmt.Errorf("state: reading signed tx: %w", err)
+	}
+	return bytes.TrimSpace(signedTx), nil
+}
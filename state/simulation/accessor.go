@@ -0,0 +1,31 @@
+// Package simulation drives pseudo-random sequences of CoreAccessor-shaped
+// operations against an in-memory ledger, checking invariants after every
+// operation, in the spirit of the Cosmos SDK's simapp module simulation.
+// It exists to catch ledger bugs (bad fee accounting, leaked delegations,
+// a miscounted PFD metric) with far more coverage than a handful of
+// hand-written unit tests could, and to reproduce any failure it finds
+// from nothing more than a seed.
+package simulation
+
+import (
+	"context"
+
+	"github.com/celestiaorg/nmt/namespace"
+
+	"github.com/celestiaorg/celestia-node/state"
+)
+
+// Accessor is the subset of CoreAccessor's tx-submitting and balance
+// methods the simulator drives. *state.CoreAccessor satisfies it directly
+// against a live celestia-core endpoint; MockAccessor satisfies it against
+// an in-memory ledger so the simulator can run fast and deterministically
+// without one.
+type Accessor interface {
+	Transfer(ctx context.Context, addr state.AccAddress, amount state.Int, gasLim uint64) (*state.TxResponse, error)
+	Delegate(ctx context.Context, delAddr state.ValAddress, amount state.Int, gasLim uint64) (*state.TxResponse, error)
+	Undelegate(ctx context.Context, delAddr state.ValAddress, amount state.Int, gasLim uint64) (*state.TxResponse, error)
+	BeginRedelegate(ctx context.Context, srcValAddr, dstValAddr state.ValAddress, amount state.Int, gasLim uint64) (*state.TxResponse, error)
+	CancelUnbondingDelegation(ctx context.Context, valAddr state.ValAddress, amount, height state.Int, gasLim uint64) (*state.TxResponse, error)
+	SubmitPayForData(ctx context.Context, nID namespace.ID, data []byte, gasLim uint64) (*state.TxResponse, error)
+	Balance(ctx context.Context) (*state.Balance, error)
+}
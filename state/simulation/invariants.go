@@ -0,0 +1,69 @@
+package simulation
+
+import "fmt"
+
+// Invariant checks one consistency property of the ledger against the
+// Snapshots taken immediately before and after an operation, returning a
+// non-nil error describing the violation if it doesn't hold.
+type Invariant func(before, after Snapshot, op LoggedOp) error
+
+// BalanceConservation asserts that a successful op removes exactly
+// simulatedFee of value from the ledger (the fee is the only value that
+// ever leaves it) and a rejected op removes none, so no operation can
+// silently create or destroy funds.
+func BalanceConservation(before, after Snapshot, op LoggedOp) error {
+	want := before.TotalValue()
+	if op.Resp != nil && op.Resp.Code == 0 {
+		want -= simulatedFee
+	}
+	if got := after.TotalValue(); got != want {
+		return fmt.Errorf("balance conservation: want total value %d after op, got %d", want, got)
+	}
+	return nil
+}
+
+// NonNegativeDelegations asserts no validator's delegation ever goes
+// negative.
+func NonNegativeDelegations(_, after Snapshot, _ LoggedOp) error {
+	for val, amt := range after.Delegations {
+		if amt < 0 {
+			return fmt.Errorf("non-negative delegations: validator %s has negative delegation %d", val, amt)
+		}
+	}
+	return nil
+}
+
+// UnbondingEntriesWellFormed asserts every pending unbonding entry carries
+// a strictly positive amount (a fully-cancelled or fully-matured entry must
+// be removed, never left at zero) and matures strictly after it was
+// created.
+func UnbondingEntriesWellFormed(_, after Snapshot, _ LoggedOp) error {
+	for _, e := range after.Unbonding {
+		if e.amount <= 0 {
+			return fmt.Errorf("unbonding entries: validator %s has non-positive amount %d", e.valAddr, e.amount)
+		}
+		if e.completeHeight <= e.creationHeight {
+			return fmt.Errorf(
+				"unbonding entries: validator %s completes at or before creation (created %d, completes %d)",
+				e.valAddr, e.creationHeight, e.completeHeight,
+			)
+		}
+	}
+	return nil
+}
+
+// NewPFDCounterInvariant returns an Invariant asserting the ledger's PFD
+// counter increments exactly once per successful (Code == 0)
+// SubmitPayForData op, and never on a rejected one or any other op.
+func NewPFDCounterInvariant() Invariant {
+	var expected int64
+	return func(_, after Snapshot, op LoggedOp) error {
+		if op.Name == OpSubmitPayForData && op.Resp != nil && op.Resp.Code == 0 {
+			expected++
+		}
+		if after.PFDCount != expected {
+			return fmt.Errorf("pfd counter: want %d successful submissions tracked, got %d", expected, after.PFDCount)
+		}
+		return nil
+	}
+}
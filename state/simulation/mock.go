@@ -0,0 +1,278 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/celestiaorg/celestia-app/app"
+	"github.com/celestiaorg/nmt/namespace"
+
+	"github.com/celestiaorg/celestia-node/state"
+)
+
+// simulatedFee is the flat amount MockAccessor deducts from the signer on
+// every tx it accepts, standing in for a real gas price * gas limit charge
+// without requiring a GasEstimator.
+const simulatedFee = 10
+
+// unbondingPeriod is the number of (simulated) blocks an unbonding entry
+// takes to mature, mirroring the staking module's unbonding time.
+const unbondingPeriod = 21
+
+// Rejection codes MockAccessor returns in a TxResponse instead of a Go
+// error, matching how a real chain rejects an invalid tx: the broadcast
+// itself succeeds, but TxResponse.Code is non-zero.
+const (
+	codeInsufficientFunds      = 5
+	codeInsufficientDelegation = 6
+	codeNoMatchingUnbonding    = 7
+)
+
+// unbondingEntry tracks one pending unbonding delegation: amount shares
+// moved out of valAddr's delegation at creationHeight, maturing at
+// completeHeight.
+type unbondingEntry struct {
+	valAddr        string
+	amount         int64
+	creationHeight int64
+	completeHeight int64
+}
+
+// MockAccessor implements Accessor against an in-memory bank and staking
+// ledger for a single signer account, so the simulator can exercise
+// CoreAccessor's tx-submitting methods without a live celestia-core
+// endpoint.
+type MockAccessor struct {
+	mu sync.Mutex
+
+	signer state.AccAddress
+
+	balances    map[string]int64
+	delegations map[string]int64
+	unbonding   []unbondingEntry
+
+	pfdCount int64
+	height   int64
+}
+
+// NewMockAccessor constructs a MockAccessor signing as signer, with signer's
+// balance seeded to initialBalance.
+func NewMockAccessor(signer state.AccAddress, initialBalance int64) *MockAccessor {
+	return &MockAccessor{
+		signer:      signer,
+		balances:    map[string]int64{signer.String(): initialBalance},
+		delegations: make(map[string]int64),
+	}
+}
+
+// Snapshot is a point-in-time, read-only copy of MockAccessor's ledger.
+// Invariants compare a Snapshot taken before an operation against one
+// taken after, rather than reading the live ledger, so a check can never
+// race the next operation.
+type Snapshot struct {
+	SignerBalance int64
+	OtherBalances map[string]int64
+	Delegations   map[string]int64
+	Unbonding     []unbondingEntry
+	PFDCount      int64
+	Height        int64
+}
+
+// TotalValue sums every unit of value the ledger currently accounts for:
+// the signer's spendable balance, every other account's balance, every
+// validator's delegation, and every pending unbonding entry. A successful
+// op should only ever reduce this by simulatedFee; a rejected op should
+// never change it at all.
+func (s Snapshot) TotalValue() int64 {
+	total := s.SignerBalance
+	for _, bal := range s.OtherBalances {
+		total += bal
+	}
+	for _, amt := range s.Delegations {
+		total += amt
+	}
+	for _, e := range s.Unbonding {
+		total += e.amount
+	}
+	return total
+}
+
+// Snapshot returns a copy of the ledger's current state.
+func (m *MockAccessor) Snapshot() Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	others := make(map[string]int64, len(m.balances))
+	for addr, bal := range m.balances {
+		if addr == m.signer.String() {
+			continue
+		}
+		others[addr] = bal
+	}
+	delegations := make(map[string]int64, len(m.delegations))
+	for val, amt := range m.delegations {
+		delegations[val] = amt
+	}
+	unbonding := make([]unbondingEntry, len(m.unbonding))
+	copy(unbonding, m.unbonding)
+
+	return Snapshot{
+		SignerBalance: m.balances[m.signer.String()],
+		OtherBalances: others,
+		Delegations:   delegations,
+		Unbonding:     unbonding,
+		PFDCount:      m.pfdCount,
+		Height:        m.height,
+	}
+}
+
+func (m *MockAccessor) okResp() *state.TxResponse {
+	return &state.TxResponse{
+		Code:   0,
+		Height: m.height,
+		TxHash: fmt.Sprintf("%064X", m.height),
+	}
+}
+
+func (m *MockAccessor) rejectedResp(code uint32) *state.TxResponse {
+	return &state.TxResponse{
+		Code:   code,
+		Height: m.height,
+	}
+}
+
+func (m *MockAccessor) Transfer(_ context.Context, addr state.AccAddress, amount state.Int, _ uint64) (*state.TxResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.height++
+
+	cost := amount.Int64() + simulatedFee
+	if m.balances[m.signer.String()] < cost {
+		return m.rejectedResp(codeInsufficientFunds), nil
+	}
+	m.balances[m.signer.String()] -= cost
+	m.balances[addr.String()] += amount.Int64()
+	return m.okResp(), nil
+}
+
+func (m *MockAccessor) Delegate(_ context.Context, delAddr state.ValAddress, amount state.Int, _ uint64) (*state.TxResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.height++
+
+	cost := amount.Int64() + simulatedFee
+	if m.balances[m.signer.String()] < cost {
+		return m.rejectedResp(codeInsufficientFunds), nil
+	}
+	m.balances[m.signer.String()] -= cost
+	m.delegations[delAddr.String()] += amount.Int64()
+	return m.okResp(), nil
+}
+
+func (m *MockAccessor) Undelegate(_ context.Context, delAddr state.ValAddress, amount state.Int, _ uint64) (*state.TxResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.height++
+
+	key := delAddr.String()
+	amt := amount.Int64()
+	if m.delegations[key] < amt {
+		return m.rejectedResp(codeInsufficientDelegation), nil
+	}
+	if m.balances[m.signer.String()] < simulatedFee {
+		return m.rejectedResp(codeInsufficientFunds), nil
+	}
+	m.balances[m.signer.String()] -= simulatedFee
+	m.delegations[key] -= amt
+	m.unbonding = append(m.unbonding, unbondingEntry{
+		valAddr:        key,
+		amount:         amt,
+		creationHeight: m.height,
+		completeHeight: m.height + unbondingPeriod,
+	})
+	return m.okResp(), nil
+}
+
+func (m *MockAccessor) BeginRedelegate(
+	_ context.Context,
+	srcValAddr, dstValAddr state.ValAddress,
+	amount state.Int,
+	_ uint64,
+) (*state.TxResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.height++
+
+	srcKey := srcValAddr.String()
+	amt := amount.Int64()
+	if m.delegations[srcKey] < amt {
+		return m.rejectedResp(codeInsufficientDelegation), nil
+	}
+	if m.balances[m.signer.String()] < simulatedFee {
+		return m.rejectedResp(codeInsufficientFunds), nil
+	}
+	m.balances[m.signer.String()] -= simulatedFee
+	m.delegations[srcKey] -= amt
+	m.delegations[dstValAddr.String()] += amt
+	return m.okResp(), nil
+}
+
+func (m *MockAccessor) CancelUnbondingDelegation(
+	_ context.Context,
+	valAddr state.ValAddress,
+	amount, height state.Int,
+	_ uint64,
+) (*state.TxResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.height++
+
+	key := valAddr.String()
+	amt := amount.Int64()
+	creationHeight := height.Int64()
+
+	for i, e := range m.unbonding {
+		if e.valAddr != key || e.creationHeight != creationHeight || e.amount < amt {
+			continue
+		}
+		if m.balances[m.signer.String()] < simulatedFee {
+			return m.rejectedResp(codeInsufficientFunds), nil
+		}
+		m.balances[m.signer.String()] -= simulatedFee
+		e.amount -= amt
+		if e.amount == 0 {
+			m.unbonding = append(m.unbonding[:i], m.unbonding[i+1:]...)
+		} else {
+			m.unbonding[i] = e
+		}
+		m.delegations[key] += amt
+		return m.okResp(), nil
+	}
+	return m.rejectedResp(codeNoMatchingUnbonding), nil
+}
+
+func (m *MockAccessor) SubmitPayForData(_ context.Context, _ namespace.ID, _ []byte, _ uint64) (*state.TxResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.height++
+
+	if m.balances[m.signer.String()] < simulatedFee {
+		return m.rejectedResp(codeInsufficientFunds), nil
+	}
+	m.balances[m.signer.String()] -= simulatedFee
+	resp := m.okResp()
+	m.pfdCount++
+	return resp, nil
+}
+
+func (m *MockAccessor) Balance(context.Context) (*state.Balance, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return &state.Balance{
+		Denom:  app.BondDenom,
+		Amount: sdktypes.NewInt(m.balances[m.signer.String()]),
+	}, nil
+}
@@ -0,0 +1,56 @@
+package simulation
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/celestiaorg/celestia-node/state"
+)
+
+var (
+	flagSeed      = flag.Int64("SimulationSeed", 42, "seed for the state module simulation's deterministic RNG")
+	flagNumBlocks = flag.Int("SimulationNumBlocks", 50, "number of blocks the state module simulation runs")
+	flagBlockSize = flag.Int("SimulationBlockSize", 30, "number of operations per simulated block")
+)
+
+const (
+	simNumRecipients = 10
+	simNumValidators = 5
+	simInitBalance   = 1_000_000
+)
+
+// TestFullStateSimulation drives a pseudo-random sequence of Transfer,
+// Delegate, Undelegate, BeginRedelegate, CancelUnbondingDelegation, and
+// SubmitPayForData calls against a MockAccessor, failing with a
+// replayable transaction log on the first invariant violation.
+//
+// A CI failure can be reproduced locally with:
+//
+//	go test ./state/simulation/... -run TestFullStateSimulation \
+//		-SimulationSeed=<seed> -SimulationNumBlocks=<n> -SimulationBlockSize=<n>
+func TestFullStateSimulation(t *testing.T) {
+	rng := rand.New(rand.NewSource(*flagSeed))
+
+	signer := sdktypes.AccAddress([]byte("sim-signer-address00"))
+	mock := NewMockAccessor(signer, simInitBalance)
+
+	recipients := make([]state.AccAddress, simNumRecipients)
+	for i := range recipients {
+		recipients[i] = sdktypes.AccAddress([]byte(fmt.Sprintf("sim-recipient-%02d00", i)))
+	}
+	validators := make([]state.ValAddress, simNumValidators)
+	for i := range validators {
+		validators[i] = sdktypes.ValAddress([]byte(fmt.Sprintf("sim-validator-%02d00", i)))
+	}
+
+	sim := NewSimulator(rng, mock, mock, validators, recipients)
+
+	if report := sim.Run(context.Background(), *flagSeed, *flagNumBlocks, *flagBlockSize); report != nil {
+		t.Fatalf("%s", report)
+	}
+}
@@ -0,0 +1,238 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/celestiaorg/nmt/namespace"
+
+	"github.com/celestiaorg/celestia-node/state"
+)
+
+// defaultGasLim is the gas limit the simulator passes to every tx-submitting
+// op; MockAccessor doesn't meter gas, so any fixed value exercises the
+// call paths identically.
+const defaultGasLim = 200_000
+
+// OpName identifies which CoreAccessor operation a LoggedOp executed.
+type OpName string
+
+const (
+	OpTransfer         OpName = "Transfer"
+	OpDelegate         OpName = "Delegate"
+	OpUndelegate       OpName = "Undelegate"
+	OpBeginRedelegate  OpName = "BeginRedelegate"
+	OpCancelUnbonding  OpName = "CancelUnbondingDelegation"
+	OpSubmitPayForData OpName = "SubmitPayForData"
+)
+
+// LoggedOp is one operation the simulator executed, recorded so a failing
+// run can be described and replayed from its seed.
+type LoggedOp struct {
+	Block int
+	Index int
+	Name  OpName
+	Args  string
+	Resp  *state.TxResponse
+	Err   error
+}
+
+func (op LoggedOp) String() string {
+	status := "ok"
+	switch {
+	case op.Err != nil:
+		status = fmt.Sprintf("error: %v", op.Err)
+	case op.Resp != nil && op.Resp.Code != 0:
+		status = fmt.Sprintf("rejected: code %d", op.Resp.Code)
+	}
+	return fmt.Sprintf("block=%d op=%d %s(%s) -> %s", op.Block, op.Index, op.Name, op.Args, status)
+}
+
+// FailureReport describes the first invariant violation a Run hit, with
+// the full preceding operation log so the failure can be reproduced
+// deterministically by rerunning with the same seed, block count, and
+// block size.
+type FailureReport struct {
+	Seed  int64
+	Block int
+	Op    LoggedOp
+	Err   error
+	Log   []LoggedOp
+}
+
+func (r *FailureReport) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "simulation failed at seed=%d block=%d op=%d: %v\n", r.Seed, r.Block, r.Op.Index, r.Err)
+	b.WriteString("replayable transaction log:\n")
+	for _, op := range r.Log {
+		b.WriteString("  " + op.String() + "\n")
+	}
+	return b.String()
+}
+
+// Simulator generates pseudo-random sequences of CoreAccessor-shaped
+// operations against an Accessor and checks invariants against the ledger
+// state a MockAccessor reports after every one.
+type Simulator struct {
+	rng        *rand.Rand
+	accessor   Accessor
+	mock       *MockAccessor
+	validators []state.ValAddress
+	recipients []state.AccAddress
+	invariants []Invariant
+
+	log []LoggedOp
+}
+
+// NewSimulator constructs a Simulator that drives accessor with operations
+// generated from rng, targeting the given validators and recipients, and
+// checks invariants against the ledger state mock reports after every
+// operation. mock is usually accessor itself; they're taken separately
+// because Accessor deliberately narrows away the Snapshot method
+// invariants need.
+func NewSimulator(
+	rng *rand.Rand,
+	accessor Accessor,
+	mock *MockAccessor,
+	validators []state.ValAddress,
+	recipients []state.AccAddress,
+) *Simulator {
+	return &Simulator{
+		rng:        rng,
+		accessor:   accessor,
+		mock:       mock,
+		validators: validators,
+		recipients: recipients,
+		invariants: []Invariant{
+			BalanceConservation,
+			NonNegativeDelegations,
+			UnbondingEntriesWellFormed,
+			NewPFDCounterInvariant(),
+		},
+	}
+}
+
+// Run generates numBlocks blocks of blockSize random operations each
+// against the Simulator's Accessor, checking every invariant after every
+// operation. It returns a FailureReport describing the first violation it
+// hits, or nil if the whole run passed.
+func (s *Simulator) Run(ctx context.Context, seed int64, numBlocks, blockSize int) *FailureReport {
+	for block := 0; block < numBlocks; block++ {
+		for i := 0; i < blockSize; i++ {
+			before := s.mock.Snapshot()
+
+			name, args, resp, err := s.genAndExec(ctx)
+			logged := LoggedOp{Block: block, Index: i, Name: name, Args: args, Resp: resp, Err: err}
+			s.log = append(s.log, logged)
+
+			after := s.mock.Snapshot()
+			for _, inv := range s.invariants {
+				if viErr := inv(before, after, logged); viErr != nil {
+					return &FailureReport{Seed: seed, Block: block, Op: logged, Err: viErr, Log: s.log}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Simulator) genAndExec(ctx context.Context) (OpName, string, *state.TxResponse, error) {
+	switch s.rng.Intn(6) {
+	case 0:
+		return s.simulateTransfer(ctx)
+	case 1:
+		return s.simulateDelegate(ctx)
+	case 2:
+		return s.simulateUndelegate(ctx)
+	case 3:
+		return s.simulateBeginRedelegate(ctx)
+	case 4:
+		return s.simulateCancelUnbonding(ctx)
+	default:
+		return s.simulatePayForData(ctx)
+	}
+}
+
+func (s *Simulator) randomAmount() state.Int {
+	return sdktypes.NewInt(int64(1 + s.rng.Intn(1000)))
+}
+
+func (s *Simulator) randomRecipient() state.AccAddress {
+	return s.recipients[s.rng.Intn(len(s.recipients))]
+}
+
+func (s *Simulator) randomValidator() state.ValAddress {
+	return s.validators[s.rng.Intn(len(s.validators))]
+}
+
+func (s *Simulator) simulateTransfer(ctx context.Context) (OpName, string, *state.TxResponse, error) {
+	addr := s.randomRecipient()
+	amount := s.randomAmount()
+	resp, err := s.accessor.Transfer(ctx, addr, amount, defaultGasLim)
+	return OpTransfer, fmt.Sprintf("to=%s amount=%s", addr, amount), resp, err
+}
+
+func (s *Simulator) simulateDelegate(ctx context.Context) (OpName, string, *state.TxResponse, error) {
+	val := s.randomValidator()
+	amount := s.randomAmount()
+	resp, err := s.accessor.Delegate(ctx, val, amount, defaultGasLim)
+	return OpDelegate, fmt.Sprintf("val=%s amount=%s", val, amount), resp, err
+}
+
+func (s *Simulator) simulateUndelegate(ctx context.Context) (OpName, string, *state.TxResponse, error) {
+	val := s.randomValidator()
+	amount := s.randomAmount()
+	resp, err := s.accessor.Undelegate(ctx, val, amount, defaultGasLim)
+	return OpUndelegate, fmt.Sprintf("val=%s amount=%s", val, amount), resp, err
+}
+
+func (s *Simulator) simulateBeginRedelegate(ctx context.Context) (OpName, string, *state.TxResponse, error) {
+	src := s.randomValidator()
+	dst := s.randomValidator()
+	amount := s.randomAmount()
+	resp, err := s.accessor.BeginRedelegate(ctx, src, dst, amount, defaultGasLim)
+	return OpBeginRedelegate, fmt.Sprintf("src=%s dst=%s amount=%s", src, dst, amount), resp, err
+}
+
+// simulateCancelUnbonding targets a real pending unbonding entry about half
+// the time (exercising the accepted path) and random, usually-nonexistent
+// parameters the rest of the time (exercising the rejection path).
+func (s *Simulator) simulateCancelUnbonding(ctx context.Context) (OpName, string, *state.TxResponse, error) {
+	snap := s.mock.Snapshot()
+
+	var (
+		val    state.ValAddress
+		amount state.Int
+		height state.Int
+	)
+	if len(snap.Unbonding) > 0 && s.rng.Intn(2) == 0 {
+		e := snap.Unbonding[s.rng.Intn(len(snap.Unbonding))]
+		var err error
+		val, err = sdktypes.ValAddressFromBech32(e.valAddr)
+		if err != nil {
+			val = s.randomValidator()
+		}
+		amount = sdktypes.NewInt(e.amount)
+		height = sdktypes.NewInt(e.creationHeight)
+	} else {
+		val = s.randomValidator()
+		amount = s.randomAmount()
+		height = sdktypes.NewInt(int64(s.rng.Intn(100)))
+	}
+
+	resp, err := s.accessor.CancelUnbondingDelegation(ctx, val, amount, height, defaultGasLim)
+	return OpCancelUnbonding, fmt.Sprintf("val=%s amount=%s height=%s", val, amount, height), resp, err
+}
+
+func (s *Simulator) simulatePayForData(ctx context.Context) (OpName, string, *state.TxResponse, error) {
+	data := make([]byte, 1+s.rng.Intn(256))
+	_, _ = s.rng.Read(data)
+	nID := namespace.ID(fmt.Sprintf("sim-ns-%04d", s.rng.Intn(10)))
+
+	resp, err := s.accessor.SubmitPayForData(ctx, nID, data, defaultGasLim)
+	return OpSubmitPayForData, fmt.Sprintf("namespace=%x size=%d", nID, len(data)), resp, err
+}